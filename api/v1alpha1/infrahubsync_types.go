@@ -34,9 +34,44 @@ type InfrahubSyncSpec struct {
 
 	// Destination contains the destination information for the resource
 	Destination InfrahubSyncDestination `json:"destination,omitempty" protobuf:"bytes,2,name=destination"`
+
+	// If true, Infrahub is not queried and the VidraResources of this sync are suspended as well. (default: false)
+	// +kubebuilder:validation:Optional
+	Suspend bool `json:"suspend,omitempty" protobuf:"varint,3,opt,name=suspend"`
+
+	// How often Infrahub is queried (e.g. 5m), overrides requeueSyncAfter of the operator config
+	// +kubebuilder:validation:Optional
+	SyncInterval *metav1.Duration `json:"syncInterval,omitempty" protobuf:"bytes,4,opt,name=syncInterval"`
+
+	// Retry configures the exponential backoff after a failed sync
+	// +kubebuilder:validation:Optional
+	Retry *RetryStrategy `json:"retry,omitempty" protobuf:"bytes,5,opt,name=retry"`
+
+	// Random delay of up to this percentage added to the sync interval and the backoff, so syncs do not query Infrahub at the same time
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	JitterPercent int32 `json:"jitterPercent,omitempty" protobuf:"varint,6,opt,name=jitterPercent"`
+}
+
+// RetryStrategy defines the exponential backoff after a failed sync
+type RetryStrategy struct {
+	// Number of retries with backoff after a failure, afterwards the sync interval is used again. If not set, it retries with backoff until the sync succeeds
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	Limit int32 `json:"limit,omitempty"`
+
+	// Delay before the first retry, doubled with every further failure (default: 10s)
+	// +kubebuilder:validation:Optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+
+	// Upper bound of the delay between retries (default: 5m)
+	// +kubebuilder:validation:Optional
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
 }
 
 // VidraResourceSource contains the source information for the resource
+// +kubebuilder:validation:XValidation:rule="(has(self.artefactName) && size(self.artefactName) > 0) || has(self.selector)",message="either artefactName or selector must be set"
 type InfrahubSyncSource struct {
 	// URL for the Infrahub API (e.g., https://infrahub.example.com)
 	// +kubebuilder:validation:Required
@@ -53,15 +88,143 @@ type InfrahubSyncSource struct {
 	// +kubebuilder:validation:Optional
 	TargetDate string `json:"targetDate,omitempty" protobuf:"bytes,3,name=targetDate"`
 
-	// Artifact name that is being handled by the operator, this is used to identify the resource in Infrahub
+	// Artifact name that is being handled by the operator, this is used to identify the resource in Infrahub.
+	// Required unless the artifacts are chosen with a selector
+	// +kubebuilder:validation:Optional
+	ArtifactName string `json:"artefactName,omitempty" protobuf:"bytes,4,opt,name=artefactName"`
+
+	// TLS configuration of the connection to Infrahub, e.g. for an instance with a certificate of an internal CA
+	// +kubebuilder:validation:Optional
+	TLS *InfrahubTLSConfig `json:"tls,omitempty" protobuf:"bytes,5,opt,name=tls"`
+
+	// HTTP(S) proxy to reach Infrahub (e.g., http://proxy.example.com:3128). If not set, the proxy of the operator environment (HTTPS_PROXY, NO_PROXY) is used
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern="^(http|https|socks5)://.+$"
+	ProxyURL string `json:"proxyURL,omitempty" protobuf:"bytes,6,opt,name=proxyURL"`
+
+	// How the artifacts are queried from Infrahub. If not set, the stored query (queryName) of the operator config is used
+	// +kubebuilder:validation:Optional
+	Query *ArtifactQuery `json:"query,omitempty" protobuf:"bytes,7,opt,name=query"`
+
+	// Selects many artifacts instead of the single artifact name, one VidraResource is created per matched artifact.
+	// Selectors are always evaluated with the GraphQL query
+	// +kubebuilder:validation:Optional
+	Selector *ArtifactSelector `json:"selector,omitempty" protobuf:"bytes,8,opt,name=selector"`
+}
+
+// ArtifactSelector selects artifacts by their definition, the group of their target objects or their name.
+// All set selectors must match
+// +kubebuilder:validation:XValidation:rule="!(has(self.nameRegex) && has(self.nameGlob))",message="nameRegex and nameGlob are mutually exclusive"
+type ArtifactSelector struct {
+	// Name of the artifact definition producing the artifacts
+	// +kubebuilder:validation:Optional
+	ArtifactDefinition string `json:"artifactDefinition,omitempty" protobuf:"bytes,1,opt,name=artifactDefinition"`
+
+	// Name of the Infrahub group (CoreStandardGroup) whose members are the targets of the artifacts
+	// +kubebuilder:validation:Optional
+	TargetGroup string `json:"targetGroup,omitempty" protobuf:"bytes,2,opt,name=targetGroup"`
+
+	// Regular expression the artifact names must match (e.g., "^leaf-.*-config$")
+	// +kubebuilder:validation:Optional
+	NameRegex string `json:"nameRegex,omitempty" protobuf:"bytes,3,opt,name=nameRegex"`
+
+	// Glob pattern the artifact names must match (e.g., "leaf-*")
+	// +kubebuilder:validation:Optional
+	NameGlob string `json:"nameGlob,omitempty" protobuf:"bytes,4,opt,name=nameGlob"`
+}
+
+// QueryMode defines how the artifacts are queried from Infrahub
+// +kubebuilder:validation:Enum=Stored;GraphQL
+type QueryMode string
+
+const (
+	// QueryModeStored runs the stored query (queryName) of the operator config via /api/query
+	QueryModeStored QueryMode = "Stored"
+	// QueryModeGraphQL sends an inline GraphQL query for CoreArtifact to /graphql/{branch}, no stored query is needed
+	QueryModeGraphQL QueryMode = "GraphQL"
+)
+
+// ArtifactQuery configures the query of the artifacts. The filters are only supported by the GraphQL mode
+// and are combined with the artifact name (name__value)
+type ArtifactQuery struct {
+	// Mode of the query (default: Stored)
+	// +kubebuilder:default:="Stored"
+	Mode QueryMode `json:"mode,omitempty" protobuf:"bytes,1,opt,name=mode,casttype=QueryMode"`
+
+	// Only artifacts of the objects with these IDs (object__ids)
+	// +kubebuilder:validation:Optional
+	ObjectIDs []string `json:"objectIDs,omitempty" protobuf:"bytes,2,rep,name=objectIDs"`
+
+	// Only artifacts with one of these statuses, e.g. Ready (status__values)
+	// +kubebuilder:validation:Optional
+	Statuses []string `json:"statuses,omitempty" protobuf:"bytes,3,rep,name=statuses"`
+
+	// Only artifacts of the artifact definition with this name (definition__name__value)
+	// +kubebuilder:validation:Optional
+	Definition string `json:"definition,omitempty" protobuf:"bytes,4,opt,name=definition"`
+
+	// Number of artifacts fetched per page, all pages are fetched (default: 100)
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000
+	// +kubebuilder:default:=100
+	PageSize int32 `json:"pageSize,omitempty" protobuf:"varint,5,opt,name=pageSize"`
+}
+
+// InfrahubTLSConfig configures how the operator verifies Infrahub and authenticates with a client certificate
+type InfrahubTLSConfig struct {
+	// PEM encoded CA certificates to verify Infrahub with, in addition to the system CAs
+	// +kubebuilder:validation:Optional
+	CABundle *CABundleSource `json:"caBundle,omitempty" protobuf:"bytes,1,opt,name=caBundle"`
+
+	// Secret of type kubernetes.io/tls with the client certificate (tls.crt) and key (tls.key) for mutual TLS
+	// +kubebuilder:validation:Optional
+	ClientCertSecret *SecretReference `json:"clientCertSecret,omitempty" protobuf:"bytes,2,opt,name=clientCertSecret"`
+
+	// If true, the certificate of Infrahub is not verified. Only use this for labs (default: false)
+	// +kubebuilder:default:=false
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty" protobuf:"varint,3,opt,name=insecureSkipVerify"`
+}
+
+// CABundleSource references a key of a ConfigMap or Secret containing PEM encoded CA certificates
+type CABundleSource struct {
+	// Kind of the object containing the CA bundle (default: ConfigMap)
+	// +kubebuilder:validation:Enum=ConfigMap;Secret
+	// +kubebuilder:default:="ConfigMap"
+	Kind string `json:"kind,omitempty" protobuf:"bytes,1,opt,name=kind"`
+
+	// Name of the ConfigMap or Secret
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
-	ArtifactName string `json:"artefactName" protobuf:"bytes,4,name=artefactName"`
+	Name string `json:"name" protobuf:"bytes,2,name=name"`
+
+	// Namespace of the ConfigMap or Secret
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace" protobuf:"bytes,3,name=namespace"`
+
+	// Key of the CA bundle in the ConfigMap or Secret (default: ca.crt)
+	// +kubebuilder:default:="ca.crt"
+	Key string `json:"key,omitempty" protobuf:"bytes,4,opt,name=key"`
+}
+
+// SecretReference references a Secret in a namespace
+type SecretReference struct {
+	// Name of the Secret
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name" protobuf:"bytes,1,name=name"`
+
+	// Namespace of the Secret
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace" protobuf:"bytes,2,name=namespace"`
 }
 
 // VidraResourceDestination contains information about where the resource will be sent
+// +kubebuilder:validation:XValidation:rule="[has(self.server) && size(self.server) > 0, has(self.cluster) && size(self.cluster) > 0, has(self.clusterSelector)].filter(x, x).size() <= 1",message="server, cluster and clusterSelector are mutually exclusive"
 type InfrahubSyncDestination struct {
-	// Only needed if you need to deploy to two Kubernetis cluster (multicluster) if set to "httlps://kubernetes.default.svc" or omitted, the operator will use the current cluster
+	// Only needed if you need to deploy to two Kubernetis cluster (multicluster) if set to "httlps://kubernetes.default.svc" or omitted, the operator will use the current cluster.
+	// Deprecated: reference a VidraCluster with cluster or clusterSelector instead
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Pattern="^(http|https)://[a-zA-Z0-9.-]+(:[0-9]+)?(?:/[a-zA-Z0-9-]+)*$"
 	Server string `json:"server,omitempty" protobuf:"bytes,1,name=server"`
@@ -73,6 +236,109 @@ type InfrahubSyncDestination struct {
 	// If true, the operator will reconcile resources based on k8s events. (default: false) - changes to the resource will trigger a reconciliation
 	// +kubebuilder:default:=false
 	ReconcileOnEvents bool `json:"reconcileOnEvents,omitempty" protobuf:"varint,4,opt,name=reconcileOnEvents"`
+
+	// If true, server-side apply forces conflicts and takes over fields that are owned by other field managers. (default: false) - otherwise a conflict fails the apply
+	// +kubebuilder:default:=false
+	ForceConflicts bool `json:"forceConflicts,omitempty" protobuf:"varint,5,opt,name=forceConflicts"`
+
+	// How manual changes (drift) of managed resources are handled: ignore, detect (report in status only) or selfHeal (overwrite). (default: selfHeal)
+	// +kubebuilder:validation:Enum=ignore;detect;selfHeal
+	// +kubebuilder:default:=selfHeal
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty" protobuf:"bytes,6,opt,name=driftPolicy"`
+
+	// Fields of matching resources which are owned by other controllers (e.g. replicas scaled by an HPA). They are not compared and applied with their live value
+	// +kubebuilder:validation:Optional
+	IgnoreDifferences []ResourceIgnoreDifferences `json:"ignoreDifferences,omitempty" protobuf:"bytes,7,rep,name=ignoreDifferences"`
+
+	// How resources which were removed from Infrahub are handled: Delete, Orphan (left in the cluster but no longer managed) or Disabled (kept and still managed). (default: Delete)
+	// +kubebuilder:validation:Enum=Delete;Orphan;Disabled
+	// +kubebuilder:default:=Delete
+	PrunePolicy PrunePolicy `json:"prunePolicy,omitempty" protobuf:"bytes,8,opt,name=prunePolicy"`
+
+	// Refuses to prune more than this percentage of the managed resources in one pass, e.g. after a faulty query. If not set, there is no limit
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	MaxPrunePercent int32 `json:"maxPrunePercent,omitempty" protobuf:"varint,9,opt,name=maxPrunePercent"`
+
+	// Name of the VidraCluster to deploy to. If neither cluster, clusterSelector nor server is set, the current cluster is used
+	// +kubebuilder:validation:Optional
+	Cluster string `json:"cluster,omitempty" protobuf:"bytes,10,opt,name=cluster"`
+
+	// Selects the VidraCluster to deploy to by its labels, exactly one VidraCluster must match
+	// +kubebuilder:validation:Optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty" protobuf:"bytes,11,opt,name=clusterSelector"`
+}
+
+// ResourceIgnoreDifferences selects resources by group, kind, name and namespace and lists the fields to ignore
+type ResourceIgnoreDifferences struct {
+	// API group of the resources, empty for the core group
+	// +kubebuilder:validation:Optional
+	Group string `json:"group,omitempty"`
+
+	// Kind of the resources (e.g. Deployment)
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+
+	// Name of the resource, if omitted all resources of the kind match
+	// +kubebuilder:validation:Optional
+	Name string `json:"name,omitempty"`
+
+	// Namespace of the resource, if omitted resources in all namespaces match
+	// +kubebuilder:validation:Optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// JSON pointers (RFC 6901) of the fields to ignore (e.g. /spec/replicas)
+	// +kubebuilder:validation:Optional
+	JSONPointers []string `json:"jsonPointers,omitempty"`
+
+	// JQ path expressions of the fields to ignore (e.g. .spec.template.spec.containers[] | select(.name == "sidecar"))
+	// +kubebuilder:validation:Optional
+	JQPathExpressions []string `json:"jqPathExpressions,omitempty"`
+}
+
+// DriftPolicy defines how manual changes of managed resources in the destination cluster are handled
+type DriftPolicy string
+
+const (
+	// Drift is neither reported nor corrected, changes of managed resources do not trigger a reconciliation
+	DriftPolicyIgnore DriftPolicy = "ignore"
+	// Drift is reported in the status of the VidraResource but not corrected
+	DriftPolicyDetect DriftPolicy = "detect"
+	// Drift is corrected by applying the manifest again
+	DriftPolicySelfHeal DriftPolicy = "selfHeal"
+)
+
+// PrunePolicy defines how resources which were removed from Infrahub or from a manifest are handled
+type PrunePolicy string
+
+const (
+	// Removed resources are deleted
+	PrunePolicyDelete PrunePolicy = "Delete"
+	// Removed resources are left in the cluster but are no longer managed by the operator
+	PrunePolicyOrphan PrunePolicy = "Orphan"
+	// Removed resources are neither deleted nor released, they stay managed until pruning is enabled again
+	PrunePolicyDisabled PrunePolicy = "Disabled"
+)
+
+// SyncedArtifact records the last synced version of an artifact
+type SyncedArtifact struct {
+	// ID of the artifact in Infrahub
+	ArtifactID string `json:"artifactID"`
+
+	// Checksum of the artifact content handed over to the VidraResource
+	Checksum string `json:"checksum"`
+
+	// Storage ID of the downloaded artifact content in Infrahub
+	// +optional
+	StorageID string `json:"storageID,omitempty"`
+
+	// Time the artifact content was last downloaded
+	SyncedAt metav1.Time `json:"syncedAt"`
+
+	// Name of the VidraResource holding the manifest of the artifact
+	VidraResourceName string `json:"vidraResourceName"`
 }
 
 // InfrahubSyncStatus defines the observed state of InfrahubSync
@@ -80,8 +346,12 @@ type InfrahubSyncStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
-	// Checksums contains a list of checksums for synced resources
-	Checksums []string `json:"checksums,omitempty"`
+	// Artifacts are the artifacts synced to VidraResources with the checksum of their last download.
+	// Unchanged artifacts are not downloaded again, entries of artifacts removed from Infrahub are dropped
+	// +listType=map
+	// +listMapKey=artifactID
+	// +optional
+	Artifacts []SyncedArtifact `json:"artifacts,omitempty"`
 
 	// SyncState indicates the current state of the sync operation
 	// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed;Stale
@@ -92,11 +362,36 @@ type InfrahubSyncStatus struct {
 
 	// LastSyncTime indicates the last time the sync operation was performed
 	LastSyncTime metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// NextSyncTime is the time the next sync or retry is scheduled for
+	NextSyncTime metav1.Time `json:"nextSyncTime,omitempty"`
+
+	// FailureCount is the number of consecutive failed syncs, reset by a successful sync
+	FailureCount int32 `json:"failureCount,omitempty"`
+
+	// ObservedGeneration is the most recent generation of the InfrahubSync that was reconciled
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Health is the aggregated health of all VidraResources created by this sync
+	// +kubebuilder:validation:Enum=Healthy;Progressing;Degraded;Unknown
+	Health HealthStatus `json:"health,omitempty"`
+
+	// Conditions represent the latest available observations of the sync operation (e.g. CredentialsResolved, InfrahubReachable, ArtifactsFetched, Ready)
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.syncState`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Health",type=string,JSONPath=`.status.health`
+// +kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
+// +kubebuilder:printcolumn:name="Next Sync",type=string,JSONPath=`.status.nextSyncTime`
+// +kubebuilder:printcolumn:name="Suspended",type=boolean,JSONPath=`.spec.suspend`
 
 // InfrahubSync is the Schema for the infrahubsyncs API
 type InfrahubSync struct {
@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VidraClusterSpec defines a remote Kubernetes cluster resources can be deployed to
+// +kubebuilder:validation:XValidation:rule="self.credentialsType == 'ServiceAccountToken' ? has(self.tokenFile) : has(self.credentialsSecretRef)",message="tokenFile is required for credentialsType ServiceAccountToken, credentialsSecretRef for all other types"
+type VidraClusterSpec struct {
+	// URL of the Kubernetes API server of the cluster (e.g., https://10.0.0.1:6443), overrides the server of the kubeconfig
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^https?://.+$"
+	Server string `json:"server" protobuf:"bytes,1,name=server"`
+
+	// How the operator authenticates against the cluster (default: Kubeconfig)
+	// +kubebuilder:default:="Kubeconfig"
+	CredentialsType ClusterCredentialsType `json:"credentialsType,omitempty" protobuf:"bytes,5,opt,name=credentialsType,casttype=ClusterCredentialsType"`
+
+	// Secret with the credentials of the cluster, the keys depend on the credentialsType. Not used for ServiceAccountToken
+	// +kubebuilder:validation:Optional
+	CredentialsSecretRef *ClusterCredentialsReference `json:"credentialsSecretRef,omitempty" protobuf:"bytes,2,opt,name=credentialsSecretRef"`
+
+	// Path of a projected service account token in the operator pod, required for credentialsType ServiceAccountToken.
+	// The token is read again when it is rotated
+	// +kubebuilder:validation:Optional
+	TokenFile string `json:"tokenFile,omitempty" protobuf:"bytes,6,opt,name=tokenFile"`
+
+	// PEM encoded CA certificates to verify the API server with, overrides the CA of the kubeconfig or the credentials Secret
+	// +kubebuilder:validation:Optional
+	CABundle *CABundleSource `json:"caBundle,omitempty" protobuf:"bytes,3,opt,name=caBundle"`
+
+	// If true, the certificate of the API server is not verified. Only use this for labs (default: false)
+	// +kubebuilder:default:=false
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty" protobuf:"varint,4,opt,name=insecureSkipVerify"`
+}
+
+// ClusterCredentialsType defines how the operator authenticates against a remote cluster
+// +kubebuilder:validation:Enum=Kubeconfig;Token;ClientCertificate;ServiceAccountToken
+type ClusterCredentialsType string
+
+const (
+	// ClusterCredentialsKubeconfig reads a kubeconfig from the key kubeconfig of the Secret. Exec and auth provider
+	// plugins are not supported, as they cannot run inside the operator
+	ClusterCredentialsKubeconfig ClusterCredentialsType = "Kubeconfig"
+	// ClusterCredentialsToken reads a bearer token from the key token and an optional CA from the key ca.crt of the
+	// Secret, e.g. a Secret of type kubernetes.io/service-account-token of the remote cluster
+	ClusterCredentialsToken ClusterCredentialsType = "Token"
+	// ClusterCredentialsClientCertificate reads a client certificate from the keys tls.crt and tls.key and an optional
+	// CA from the key ca.crt of the Secret, e.g. a Secret of type kubernetes.io/tls
+	ClusterCredentialsClientCertificate ClusterCredentialsType = "ClientCertificate"
+	// ClusterCredentialsServiceAccountToken sends the projected service account token of tokenFile, e.g. for clusters
+	// trusting the issuer of the local cluster
+	ClusterCredentialsServiceAccountToken ClusterCredentialsType = "ServiceAccountToken"
+)
+
+// ClusterCredentialsReference references the Secret with the credentials of a cluster
+type ClusterCredentialsReference struct {
+	// Name of the Secret
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name" protobuf:"bytes,1,name=name"`
+
+	// Namespace of the Secret
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace" protobuf:"bytes,2,name=namespace"`
+
+	// Context of the kubeconfig to use, only for credentialsType Kubeconfig. If not set, the current context of the kubeconfig is used
+	// +kubebuilder:validation:Optional
+	Context string `json:"context,omitempty" protobuf:"bytes,3,opt,name=context"`
+}
+
+// VidraClusterStatus defines the observed state of VidraCluster
+type VidraClusterStatus struct {
+	// KubernetesVersion is the version reported by the API server (e.g., v1.32.1)
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// LastProbeTime is the last time the connection to the cluster was checked
+	LastProbeTime metav1.Time `json:"lastProbeTime,omitempty"`
+
+	// ObservedGeneration is the most recent generation of the VidraCluster that was checked
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the cluster (e.g. Connected, VersionDetected)
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// Condition types used in the status of VidraCluster
+const (
+	// Indicates the credentials were resolved and the API server of the cluster is reachable
+	ConditionConnected = "Connected"
+	// Indicates the Kubernetes version of the cluster was read from the API server
+	ConditionVersionDetected = "VersionDetected"
+)
+
+// Condition reasons used in the status of VidraCluster
+const (
+	ReasonConnectionFailed = "ConnectionFailed"
+	ReasonUnauthorized     = "Unauthorized"
+	ReasonVersionUnknown   = "VersionUnknown"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Server",type=string,JSONPath=`.spec.server`
+// +kubebuilder:printcolumn:name="Connected",type=string,JSONPath=`.status.conditions[?(@.type=="Connected")].status`
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=`.status.kubernetesVersion`
+// +kubebuilder:printcolumn:name="Last Probe",type=date,JSONPath=`.status.lastProbeTime`
+
+// VidraCluster is the Schema for the vidraclusters API
+type VidraCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// VidraClusterSpec defines the remote cluster
+	Spec VidraClusterSpec `json:"spec,omitempty"`
+	// VidraClusterStatus defines the observed state of VidraCluster
+	Status VidraClusterStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VidraClusterList contains a list of VidraCluster
+type VidraClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VidraCluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VidraCluster{}, &VidraClusterList{})
+}
@@ -31,8 +31,29 @@ type VidraResourceSpec struct {
 	// Manifest contains the manifest information for the resource
 	Manifest string `json:"manifest,omitempty" protobuf:"bytes,2,name=manifest"`
 
+	// ManifestFrom references the chunks of a manifest too large to be stored inline, used instead of Manifest
+	// +kubebuilder:validation:Optional
+	ManifestFrom *ManifestSource `json:"manifestFrom,omitempty" protobuf:"bytes,7,opt,name=manifestFrom"`
+
 	// The last time the resource was reconciled
 	ReconciledAt metav1.Time `json:"reconciledAt,omitempty" protobuf:"bytes,5,name=reconciledAt"`
+
+	// If true, the manifest is neither applied nor pruned, deleting the resource still cleans up. (default: false)
+	// +kubebuilder:validation:Optional
+	Suspend bool `json:"suspend,omitempty" protobuf:"varint,6,opt,name=suspend"`
+}
+
+// ManifestSource references a manifest stored in chunks in Secrets, each chunk under the key "manifest"
+type ManifestSource struct {
+	// Namespace of the Secrets holding the chunks
+	Namespace string `json:"namespace" protobuf:"bytes,1,name=namespace"`
+
+	// Names of the Secrets holding the chunks of the manifest in order
+	// +kubebuilder:validation:MinItems=1
+	Secrets []string `json:"secrets" protobuf:"bytes,2,rep,name=secrets"`
+
+	// SHA-256 hex digest of the complete manifest, verified when the chunks are joined
+	SHA256 string `json:"sha256" protobuf:"bytes,3,name=sha256"`
 }
 
 // VidraResourceStatus defines the observed state of VidraResource
@@ -50,6 +71,22 @@ type VidraResourceStatus struct {
 	LastError string `json:"lastError,omitempty"`
 	// LastSyncTime indicates the last time the resource was synchronized
 	LastSyncTime metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// ObservedGeneration is the most recent generation of the VidraResource that was reconciled
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Drifted lists managed resources whose live state differs from the manifest (only reported with driftPolicy detect)
+	Drifted []DriftedResource `json:"drifted,omitempty"`
+
+	// Health is the aggregated health of all managed resources (the worst health of a single resource)
+	// +kubebuilder:validation:Enum=Healthy;Progressing;Degraded;Unknown
+	Health HealthStatus `json:"health,omitempty"`
+
+	// Conditions represent the latest available observations of the deployment (e.g. Applied, Pruned, Healthy, Ready)
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
 type ManagedResourceStatus struct {
@@ -61,6 +98,28 @@ type ManagedResourceStatus struct {
 	Name string `json:"name"`
 	// Namespace of the resource
 	Namespace string `json:"namespace,omitempty"`
+	// SyncWave the resource was applied in
+	SyncWave int `json:"syncWave,omitempty"`
+	// LastAppliedHash is the hash of the manifest document last applied, used to tell manifest changes from drift
+	LastAppliedHash string `json:"lastAppliedHash,omitempty"`
+	// Health of the live resource in the destination cluster
+	// +kubebuilder:validation:Enum=Healthy;Progressing;Degraded;Unknown
+	Health HealthStatus `json:"health,omitempty"`
+	// HealthMessage explains why the resource is not healthy
+	HealthMessage string `json:"healthMessage,omitempty"`
+}
+
+type DriftedResource struct {
+	// Kind of the resource (e.g., Deployment, Service)
+	Kind string `json:"kind"`
+	// APIVersion of the resource (e.g., apps/v1)
+	APIVersion string `json:"apiVersion"`
+	// Name of the resource
+	Name string `json:"name"`
+	// Namespace of the resource
+	Namespace string `json:"namespace,omitempty"`
+	// Diff summarizes the fields which differ from the manifest (e.g. "spec.replicas: want 3, got 5")
+	Diff []string `json:"diff,omitempty"`
 }
 
 type State string
@@ -76,9 +135,73 @@ const (
 	StateStale State = "Stale"
 )
 
+// HealthStatus describes the health of the live resources in the destination cluster
+type HealthStatus string
+
+const (
+	// Indicates the resource reached its desired state
+	HealthHealthy HealthStatus = "Healthy"
+	// Indicates the resource is not yet healthy but still making progress (e.g. a rollout)
+	HealthProgressing HealthStatus = "Progressing"
+	// Indicates the resource failed or can not reach its desired state
+	HealthDegraded HealthStatus = "Degraded"
+	// Indicates the health of the resource could not be assessed
+	HealthUnknown HealthStatus = "Unknown"
+)
+
+// Condition types used in the status of InfrahubSync and VidraResource
+const (
+	// Indicates the Infrahub credentials were found in a Secret
+	ConditionCredentialsResolved = "CredentialsResolved"
+	// Indicates the login to and the artifact query against Infrahub succeeded
+	ConditionInfrahubReachable = "InfrahubReachable"
+	// Indicates all artifacts were downloaded and handed over to their VidraResources
+	ConditionArtifactsFetched = "ArtifactsFetched"
+	// Indicates all resources of the manifest were applied to the destination cluster
+	ConditionApplied = "Applied"
+	// Indicates all resources which were removed from the manifest are cleaned up
+	ConditionPruned = "Pruned"
+	// Indicates all managed resources report a healthy state in the destination cluster
+	ConditionHealthy = "Healthy"
+	// Indicates the last reconciliation of the current generation succeeded
+	ConditionReady = "Ready"
+	// Indicates reconciliation is suspended by spec.suspend
+	ConditionSuspended = "Suspended"
+)
+
+// Condition reasons used in the status of InfrahubSync and VidraResource
+const (
+	ReasonSucceeded              = "Succeeded"
+	ReasonReconciling            = "Reconciling"
+	ReasonFailed                 = "Failed"
+	ReasonCredentialsNotFound    = "CredentialsNotFound"
+	ReasonLoginFailed            = "LoginFailed"
+	ReasonInvalidConnection      = "InvalidConnection"
+	ReasonQueryFailed            = "QueryFailed"
+	ReasonInvalidSelector        = "InvalidSelector"
+	ReasonDownloadFailed         = "DownloadFailed"
+	ReasonChecksumMismatch       = "ChecksumMismatch"
+	ReasonArtifactTooLarge       = "ArtifactTooLarge"
+	ReasonSyncFailed             = "SyncFailed"
+	ReasonDestinationUnavailable = "DestinationUnavailable"
+	ReasonInvalidManifest        = "InvalidManifest"
+	ReasonApplyFailed            = "ApplyFailed"
+	ReasonPruneFailed            = "PruneFailed"
+	ReasonWaitingForSyncWave     = "WaitingForSyncWave"
+	ReasonPruneDisabled          = "PruneDisabled"
+	ReasonPruneThresholdExceeded = "PruneThresholdExceeded"
+	ReasonSuspended              = "Suspended"
+	ReasonResumed                = "Resumed"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.DeployState`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Health",type=string,JSONPath=`.status.health`
+// +kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
+// +kubebuilder:printcolumn:name="Suspended",type=boolean,JSONPath=`.spec.suspend`
 
 // VidraResource is the Schema for the Vidraresources API
 type VidraResource struct {
@@ -29,6 +29,7 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -36,6 +37,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	"github.com/infrahub-operator/vidra/internal/adapter/k8s"
 	"github.com/infrahub-operator/vidra/internal/controller"
 	// +kubebuilder:scaffold:imports
 )
@@ -45,6 +47,9 @@ var (
 	setupLog = ctrl.Log.WithName("setup")
 )
 
+// webhookEventBuffer is the number of InfrahubSyncs triggered by webhooks that can wait for the controller
+const webhookEventBuffer = 100
+
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
@@ -56,12 +61,15 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var webhookReceiverAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&webhookReceiverAddr, "webhook-receiver-bind-address", ":8082", "The address the Infrahub webhook "+
+		"receiver binds to, or leave as 0 to disable the receiver.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -144,21 +152,46 @@ func main() {
 		os.Exit(1)
 	}
 
+	// The clients of remote clusters are shared, so clients dropped by the cluster probe are rebuilt for the apply as well
+	multiclusterFactory := k8s.NewDynamicMulticlusterFactory()
 	if err = (&controller.VidraResourceReconciler{
-		Client:     mgr.GetClient(),
-		Scheme:     mgr.GetScheme(),
-		RESTMapper: mgr.GetRESTMapper(),
+		Client:                     mgr.GetClient(),
+		Scheme:                     mgr.GetScheme(),
+		RESTMapper:                 mgr.GetRESTMapper(),
+		DynamicMulticlusterFactory: multiclusterFactory,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "VidraResource")
 		os.Exit(1)
 	}
+	var webhookEvents chan event.GenericEvent
+	if webhookReceiverAddr != "0" {
+		webhookEvents = make(chan event.GenericEvent, webhookEventBuffer)
+		if err := mgr.Add(&controller.WebhookReceiver{
+			Client:      mgr.GetClient(),
+			BindAddress: webhookReceiverAddr,
+			Events:      webhookEvents,
+			Elected:     mgr.Elected(),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up webhook receiver")
+			os.Exit(1)
+		}
+	}
 	if err = (&controller.InfrahubSyncReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		WebhookEvents: webhookEvents,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "InfrahubSync")
 		os.Exit(1)
 	}
+	if err = (&controller.VidraClusterReconciler{
+		Client:                     mgr.GetClient(),
+		Scheme:                     mgr.GetScheme(),
+		DynamicMulticlusterFactory: multiclusterFactory,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VidraCluster")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -1,7 +1,7 @@
 package infrahub
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,11 +14,36 @@ import (
 	"github.com/infrahub-operator/vidra/internal/domain"
 )
 
-type infrahubClient struct{}
+type infrahubClient struct {
+	// options configures the retries and timeouts of the requests
+	options ClientOptions
+	// tokens caches the tokens of all logins, shared by concurrent reconciles and all connections
+	tokens *tokenCache
+	// httpClients pools the HTTP clients per connection config
+	httpClients *httpClientPool
+	// limiters bound the request rate and concurrency per Infrahub instance, shared by all connections
+	limiters *limiterPool
+	// httpClient sends the requests to Infrahub
+	httpClient *http.Client
+}
 
-// NewClient returns a new InfrahubClient.
+// NewClient returns a new InfrahubClient using the proxy of the environment and the system CAs.
 func NewClient() domain.InfrahubClient {
-	return &infrahubClient{}
+	return NewClientWithOptions(DefaultClientOptions())
+}
+
+// NewClientWithOptions returns a new InfrahubClient with the given retries and timeouts.
+func NewClientWithOptions(options ClientOptions) domain.InfrahubClient {
+	httpClients := newHTTPClientPool(options.RequestTimeout)
+	// The default connection has no certificates or proxy URL to parse, so it can not fail
+	httpClient, _ := httpClients.get(domain.Connection{})
+	return &infrahubClient{
+		options:     options,
+		tokens:      &tokenCache{},
+		httpClients: httpClients,
+		limiters:    newLimiterPool(options),
+		httpClient:  httpClient,
+	}
 }
 
 var relativeFormatRegex = regexp.MustCompile(`^[a-zA-Z]+[-+]\d+[smh]$`)
@@ -70,7 +95,7 @@ func BuildURL(baseAPIURL, pathTemplate string, pathParams map[string]string, que
 }
 
 // RunQuery sends a query to the Infrahub API
-func (c *infrahubClient) RunQuery(queryName string, apiURL string, artifactName string, targetBranche string, targetDate string, token string) (*[]domain.Artifact, error) {
+func (c *infrahubClient) RunQuery(ctx context.Context, queryName string, apiURL string, artifactName string, targetBranche string, targetDate string, token domain.Token) (*[]domain.Artifact, error) {
 	// Construct the query URL
 	url, err := BuildURL(
 		apiURL,
@@ -97,41 +122,11 @@ func (c *infrahubClient) RunQuery(queryName string, apiURL string, artifactName
 		return nil, fmt.Errorf("failed to marshal query payload: %w", err)
 	}
 
-	var resp *http.Response
-	var lastErr error
-	backoff := 200 * time.Millisecond
-
-	for attempts := 0; attempts < 5; attempts++ {
-		req, err := http.NewRequest("POST", url, bytes.NewReader(payloadBytes))
-		if err != nil {
-			return nil, fmt.Errorf("failed to create query request: %w", err)
-		}
-
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err = http.DefaultClient.Do(req)
-		if err == nil && resp.StatusCode == http.StatusOK {
-			break
-		}
-		if resp != nil {
-			if cerr := resp.Body.Close(); cerr != nil {
-				fmt.Printf("warning: failed to close response body: %v\n", cerr)
-			}
-		}
-		lastErr = err
-		time.Sleep(backoff)
-		backoff *= 2
-	}
-
-	if resp == nil {
-		return nil, fmt.Errorf("query request failed after retries: %w", lastErr)
+	resp, err := c.do(ctx, "POST", url, payloadBytes, &token)
+	if err != nil {
+		return nil, fmt.Errorf("query request failed after retries: %w", err)
 	}
-	defer func() {
-		if cerr := resp.Body.Close(); cerr != nil {
-			fmt.Printf("warning: failed to close response body: %v\n", cerr)
-		}
-	}()
+	defer closeBody(resp)
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
@@ -148,67 +143,46 @@ func (c *infrahubClient) RunQuery(queryName string, apiURL string, artifactName
 	return &artifacts, nil
 }
 
-// Login authenticates with the Infrahub API and returns the authentication token
-func (c *infrahubClient) Login(apiURL, username, password string) (string, error) {
+// setAuthHeader authenticates the request with the access token or the API token
+func setAuthHeader(req *http.Request, token domain.Token) {
+	if token.APIToken {
+		req.Header.Set("X-INFRAHUB-KEY", token.Value)
+		return
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.Value))
+}
+
+// login authenticates with the Infrahub API and returns the access and refresh token
+func (c *infrahubClient) login(ctx context.Context, apiURL, username, password string) (LoginResponse, error) {
 	loginURL := fmt.Sprintf("%s/api/auth/login", apiURL)
 	loginPayload := map[string]string{"username": username, "password": password}
 
 	payloadBytes, err := json.Marshal(loginPayload)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal login payload: %w", err)
+		return LoginResponse{}, fmt.Errorf("failed to marshal login payload: %w", err)
 	}
 
-	var resp *http.Response
-	var lastErr error
-	backoff := time.Millisecond * 200
-
-	for attempts := 0; attempts < 5; attempts++ {
-		req, err := http.NewRequest("POST", loginURL, bytes.NewReader(payloadBytes))
-		if err != nil {
-			return "", fmt.Errorf("failed to create login request: %w", err)
-		}
-		req.Header.Set("Accept", "application/json")
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err = http.DefaultClient.Do(req)
-		if err == nil && resp.StatusCode == http.StatusOK {
-			break
-		}
-
-		if resp != nil {
-			if cerr := resp.Body.Close(); cerr != nil {
-				fmt.Printf("warning: failed to close response body: %v\n", cerr)
-			}
-		}
-		lastErr = err
-		time.Sleep(backoff)
-		backoff *= 2
+	resp, err := c.do(ctx, "POST", loginURL, payloadBytes, nil)
+	if err != nil {
+		return LoginResponse{}, fmt.Errorf("login request failed after retries: %w", err)
 	}
-
-	if resp == nil {
-		return "", fmt.Errorf("login request failed after retries: %w", lastErr)
-	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			fmt.Printf("warning: failed to close response body: %v\n", err)
-		}
-	}()
+	defer closeBody(resp)
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("login failed with status %s: %s", resp.Status, body)
+		return LoginResponse{}, fmt.Errorf("login failed with status %s: %s", resp.Status, body)
 	}
 
 	var loginResp LoginResponse
 	if err := json.NewDecoder(resp.Body).Decode(&loginResp); err != nil {
-		return "", fmt.Errorf("failed to decode login response: %w", err)
+		return LoginResponse{}, fmt.Errorf("failed to decode login response: %w", err)
 	}
 
-	return loginResp.Token, nil
+	return loginResp, nil
 }
 
-// DownloadArtifact downloads the artifact from the given URL and saves it to a temporary file
-func (c *infrahubClient) DownloadArtifact(apiURL string, artifactID string, targetBranche string, targetDate string, token string) (io.Reader, error) {
+// DownloadArtifact downloads the artifact from the given URL, the caller reads the returned body
+func (c *infrahubClient) DownloadArtifact(ctx context.Context, apiURL string, artifactID string, targetBranche string, targetDate string, token domain.Token) (io.Reader, error) {
 	url, err := BuildURL(
 		apiURL,
 		"/api/artifact/:artifactID",
@@ -224,38 +198,14 @@ func (c *infrahubClient) DownloadArtifact(apiURL string, artifactID string, targ
 		return nil, fmt.Errorf("failed to build URL for artifact: %v", err)
 	}
 
-	var resp *http.Response
-	var lastErr error
-	backoff := 200 * time.Millisecond
-
-	for attempts := 0; attempts < 5; attempts++ {
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
-
-		resp, err = http.DefaultClient.Do(req)
-		if err == nil && resp.StatusCode == http.StatusOK {
-			// Success
-			return resp.Body, nil
-		}
-		if resp != nil {
-			if cerr := resp.Body.Close(); cerr != nil {
-				fmt.Printf("warning: failed to close response body: %v\n", cerr)
-			}
-		}
-		lastErr = err
-		time.Sleep(backoff)
-		backoff *= 2
+	resp, err := c.do(ctx, "GET", url, nil, &token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download artifact after retries: %v", err)
 	}
-
-	if resp != nil {
+	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		if cerr := resp.Body.Close(); cerr != nil {
-			fmt.Printf("warning: failed to close response body: %v\n", cerr)
-		}
-		return nil, fmt.Errorf("failed to download artifact after retries, last status code: %d, response: %s", resp.StatusCode, body)
+		closeBody(resp)
+		return nil, fmt.Errorf("failed to download artifact, last status code: %d, response: %s", resp.StatusCode, body)
 	}
-	return nil, fmt.Errorf("failed to download artifact after retries: %v", lastErr)
+	return resp.Body, nil
 }
@@ -1,6 +1,7 @@
 package infrahub
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -8,6 +9,8 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+
+	"github.com/infrahub-operator/vidra/internal/domain"
 )
 
 var _ = Describe("IsValidTargetDateFormat", func() {
@@ -133,9 +136,9 @@ var _ = Describe("infrahubClient", func() {
 
 			}))
 
-			token, err := client.Login(server.URL, "user", "pass")
+			token, err := client.Login(context.Background(), server.URL, domain.Credentials{Username: "user", Password: "pass"})
 			Expect(err).ToNot(HaveOccurred())
-			Expect(token).To(Equal("abc123"))
+			Expect(token).To(Equal(domain.Token{Value: "abc123"}))
 		})
 
 		It("fails on bad credentials", func() {
@@ -145,10 +148,10 @@ var _ = Describe("infrahubClient", func() {
 				Expect(err).ToNot(HaveOccurred())
 			}))
 
-			token, err := client.Login(server.URL, "user", "wrongpass")
+			token, err := client.Login(context.Background(), server.URL, domain.Credentials{Username: "user", Password: "wrongpass"})
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("login failed with status"))
-			Expect(token).To(BeEmpty())
+			Expect(token.Value).To(BeEmpty())
 		})
 		It("returns error if response body is empty", func() {
 			// Create a test server that returns 200 OK but empty body
@@ -158,12 +161,12 @@ var _ = Describe("infrahubClient", func() {
 			}))
 			defer server.Close()
 
-			client := &infrahubClient{}
+			client := NewClient()
 
-			token, err := client.Login(server.URL, "user", "pass")
+			token, err := client.Login(context.Background(), server.URL, domain.Credentials{Username: "user", Password: "pass"})
 
 			Expect(err).To(HaveOccurred())
-			Expect(token).To(BeEmpty())
+			Expect(token.Value).To(BeEmpty())
 			Expect(err.Error()).To(ContainSubstring("EOF")) // JSON decode error usually returns EOF on empty body
 		})
 	})
@@ -212,9 +215,9 @@ var _ = Describe("infrahubClient", func() {
 				})
 			}))
 
-			client := &infrahubClient{}
+			client := NewClient()
 
-			result, err := client.RunQuery("test-query", server.URL, "test-artifact", "main", "2025-01-01T00:00:00Z", "token123")
+			result, err := client.RunQuery(context.Background(), "test-query", server.URL, "test-artifact", "main", "2025-01-01T00:00:00Z", domain.Token{Value: "token123"})
 			Expect(err).ToNot(HaveOccurred())
 			Expect(*result).To(HaveLen(1))
 			Expect((*result)[0].ID).To(Equal("a1"))
@@ -223,7 +226,7 @@ var _ = Describe("infrahubClient", func() {
 		})
 
 		It("fails on BuildURL error", func() {
-			result, err := client.RunQuery("test-query", "://invalid-url", "a", "b", "notadate", "token")
+			result, err := client.RunQuery(context.Background(), "test-query", "://invalid-url", "a", "b", "notadate", domain.Token{Value: "token"})
 			Expect(err).To(HaveOccurred())
 			Expect(result).To(BeNil())
 			Expect(err.Error()).To(ContainSubstring("failed to build query URL"))
@@ -236,7 +239,7 @@ var _ = Describe("infrahubClient", func() {
 				Expect(err).ToNot(HaveOccurred())
 			}))
 
-			result, err := client.RunQuery("test-query", server.URL, "a", "b", "2025-01-01T00:00:00Z", "token")
+			result, err := client.RunQuery(context.Background(), "test-query", server.URL, "a", "b", "2025-01-01T00:00:00Z", domain.Token{Value: "token"})
 			Expect(err).To(HaveOccurred())
 			Expect(result).To(BeNil())
 			Expect(err.Error()).To(ContainSubstring("query failed with status"))
@@ -249,7 +252,7 @@ var _ = Describe("infrahubClient", func() {
 				Expect(err).ToNot(HaveOccurred())
 			}))
 
-			result, err := client.RunQuery("test-query", server.URL, "a", "b", "2025-01-01T00:00:00Z", "token")
+			result, err := client.RunQuery(context.Background(), "test-query", server.URL, "a", "b", "2025-01-01T00:00:00Z", domain.Token{Value: "token"})
 			Expect(err).To(HaveOccurred())
 			Expect(result).To(BeNil())
 			Expect(err.Error()).To(ContainSubstring("failed to decode query result"))
@@ -263,9 +266,9 @@ var _ = Describe("infrahubClient", func() {
 				}))
 				defer server.Close()
 
-				client := &infrahubClient{}
+				client := NewClient()
 
-				_, err := client.RunQuery("test-query", server.URL, "test-artifact", "main", "2025-01-01T00:00:00Z", "token123")
+				_, err := client.RunQuery(context.Background(), "test-query", server.URL, "test-artifact", "main", "2025-01-01T00:00:00Z", domain.Token{Value: "token123"})
 
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("EOF")) // Likely JSON decoding will fail due to empty body
@@ -287,7 +290,7 @@ var _ = Describe("infrahubClient", func() {
 			artifactID = "test-artifact-id"
 			branch = "main"
 			date = "2025-01-01T00:00:00Z"
-			client = &infrahubClient{}
+			client = NewClient().(*infrahubClient)
 		})
 
 		AfterEach(func() {
@@ -309,7 +312,7 @@ var _ = Describe("infrahubClient", func() {
 			}))
 
 			apiURL = server.URL
-			reader, err := client.DownloadArtifact(apiURL, artifactID, branch, date, "mock-token")
+			reader, err := client.DownloadArtifact(context.Background(), apiURL, artifactID, branch, date, domain.Token{Value: "mock-token"})
 			Expect(err).NotTo(HaveOccurred())
 
 			content, err := io.ReadAll(reader)
@@ -319,7 +322,7 @@ var _ = Describe("infrahubClient", func() {
 
 		It("fails to send GET request with malformed URL", func() {
 			apiURL = ":::invalid-url"
-			reader, err := client.DownloadArtifact(apiURL, artifactID, branch, date, "mock-token")
+			reader, err := client.DownloadArtifact(context.Background(), apiURL, artifactID, branch, date, domain.Token{Value: "mock-token"})
 			Expect(reader).To(BeNil())
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("failed to create request"))
@@ -329,7 +332,7 @@ var _ = Describe("infrahubClient", func() {
 		It("fails to send GET request", func() {
 			// Use a non-routable address to trigger http.Get error
 			apiURL = "http://127.0.0.1:0" // closed port
-			reader, err := client.DownloadArtifact(apiURL, artifactID, branch, date, "mock-token")
+			reader, err := client.DownloadArtifact(context.Background(), apiURL, artifactID, branch, date, domain.Token{Value: "mock-token"})
 			Expect(reader).To(BeNil())
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("failed to download artifact after retries"))
@@ -342,20 +345,20 @@ var _ = Describe("infrahubClient", func() {
 			}))
 			apiURL = server.URL
 
-			content, err := client.DownloadArtifact(apiURL, artifactID, branch, date, "token123")
+			content, err := client.DownloadArtifact(context.Background(), apiURL, artifactID, branch, date, domain.Token{Value: "token123"})
 			Expect(err).To(HaveOccurred())
 			Expect(content).To(BeNil())
 			Expect(err.Error()).To(ContainSubstring("failed to download artifact"))
 		})
 
 		It("returns error on malformed URL", func() {
-			content, err := client.DownloadArtifact(":://badurl", artifactID, branch, date, "token")
+			content, err := client.DownloadArtifact(context.Background(), ":://badurl", artifactID, branch, date, domain.Token{Value: "token"})
 			Expect(err).To(HaveOccurred())
 			Expect(content).To(BeNil())
 		})
 		It("fails to build the artifact URL", func() {
 			apiURL = "://bad-url"
-			body, err := client.DownloadArtifact(apiURL, artifactID, branch, date, "token123")
+			body, err := client.DownloadArtifact(context.Background(), apiURL, artifactID, branch, date, domain.Token{Value: "token123"})
 			Expect(err).To(HaveOccurred())
 			Expect(body).To(BeNil())
 			Expect(err.Error()).To(ContainSubstring("failed to create request"))
@@ -367,19 +370,19 @@ var _ = Describe("infrahubClient", func() {
 			}))
 
 			apiURL = server.URL
-			body, err := client.DownloadArtifact(apiURL, artifactID, branch, date, "token123")
+			body, err := client.DownloadArtifact(context.Background(), apiURL, artifactID, branch, date, domain.Token{Value: "token123"})
 			Expect(err).To(HaveOccurred())
 			Expect(body).To(BeNil())
 			Expect(err.Error()).To(ContainSubstring("last status code: 404"))
 		})
 		It("returns an error if the date format is invalid", func() {
 
-			_, err := client.DownloadArtifact(
+			_, err := client.DownloadArtifact(context.Background(),
 				"http://example.com",
 				"artifact123",
 				"main",
 				"invalid-date-format", // intentionally wrong
-				"token123",
+				domain.Token{Value: "token123"},
 			)
 
 			Expect(err).To(HaveOccurred())
@@ -0,0 +1,189 @@
+package infrahub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/infrahub-operator/vidra/internal/domain"
+)
+
+// defaultPageSize is the number of artifacts fetched per request if the query sets no page size
+const defaultPageSize = 100
+
+// groupMembersQuery fetches one page of the members of a CoreStandardGroup
+const groupMembersQuery = "query VidraGroupMembers($group: String, $offset: Int, $limit: Int) { " +
+	"CoreStandardGroup(name__value: $group) { edges { node { members(offset: $offset, limit: $limit) { count edges { node { id } } } } } } }"
+
+// QueryArtifacts fetches all artifacts matching the query from /graphql/{branch}, requesting page after page
+// until the total count of Infrahub is reached
+func (c *infrahubClient) QueryArtifacts(ctx context.Context, apiURL string, query domain.ArtifactQuery, token domain.Token) (*[]domain.Artifact, error) {
+	branch := query.Branch
+	if branch == "" {
+		branch = "main"
+	}
+	url, err := BuildURL(apiURL, "/graphql/:branch", map[string]string{"branch": branch}, map[string]string{"at": query.At})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GraphQL URL: %w", err)
+	}
+
+	pageSize := query.PageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+
+	if query.TargetGroup != "" {
+		members, err := c.groupMembers(ctx, url, query.TargetGroup, pageSize, &token)
+		if err != nil {
+			return nil, err
+		}
+		query.ObjectIDs = intersectIDs(members, query.ObjectIDs)
+		// An empty object__ids filter is not applied by Infrahub, so a group without members matches nothing
+		if len(query.ObjectIDs) == 0 {
+			return &[]domain.Artifact{}, nil
+		}
+	}
+	document, variables := artifactQueryDocument(query)
+
+	artifacts := []domain.Artifact{}
+	for offset := 0; ; offset += pageSize {
+		variables["offset"] = offset
+		variables["limit"] = pageSize
+
+		page, err := c.queryArtifactPage(ctx, url, graphQLPayload{Query: document, Variables: variables}, &token)
+		if err != nil {
+			return nil, err
+		}
+		for _, edge := range page.Edges {
+			artifacts = append(artifacts, domain.Artifact{
+				ID:        edge.Node.ID,
+				StorageID: edge.Node.StorageID.Value,
+				Checksum:  edge.Node.Checksum.Value,
+				Name:      edge.Node.Name.Value,
+			})
+		}
+
+		if len(page.Edges) == 0 || offset+len(page.Edges) >= page.Count {
+			break
+		}
+	}
+
+	return &artifacts, nil
+}
+
+// groupMembers returns the IDs of all members of the CoreStandardGroup with the name, requesting page after page
+func (c *infrahubClient) groupMembers(ctx context.Context, url, group string, pageSize int, token *domain.Token) ([]string, error) {
+	var members []string
+	for offset := 0; ; offset += pageSize {
+		payload := graphQLPayload{Query: groupMembersQuery, Variables: map[string]any{
+			"group": group, "offset": offset, "limit": pageSize,
+		}}
+
+		var result groupMembersGraphQLResult
+		if err := c.graphQL(ctx, url, payload, token, &result); err != nil {
+			return nil, err
+		}
+		if len(result.Data.CoreStandardGroup.Edges) == 0 {
+			return nil, fmt.Errorf("target group %q not found", group)
+		}
+		page := result.Data.CoreStandardGroup.Edges[0].Node.Members
+		for _, edge := range page.Edges {
+			members = append(members, edge.Node.ID)
+		}
+
+		if len(page.Edges) == 0 || offset+len(page.Edges) >= page.Count {
+			return members, nil
+		}
+	}
+}
+
+// intersectIDs returns the IDs of the group members that are also in ids, or all members if ids is empty
+func intersectIDs(members, ids []string) []string {
+	if len(ids) == 0 {
+		return members
+	}
+	var result []string
+	for _, member := range members {
+		if slices.Contains(ids, member) {
+			result = append(result, member)
+		}
+	}
+	return result
+}
+
+// queryArtifactPage sends the GraphQL query for one page of artifacts
+func (c *infrahubClient) queryArtifactPage(ctx context.Context, url string, payload graphQLPayload, token *domain.Token) (*artifactPage, error) {
+	var result artifactGraphQLResult
+	if err := c.graphQL(ctx, url, payload, token, &result); err != nil {
+		return nil, err
+	}
+	return &result.Data.CoreArtifact, nil
+}
+
+// graphQL sends a GraphQL query and decodes the response into result, errors of the response are returned
+func (c *infrahubClient) graphQL(ctx context.Context, url string, payload graphQLPayload, token *domain.Token, result graphQLResult) error {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal GraphQL payload: %w", err)
+	}
+
+	resp, err := c.do(ctx, "POST", url, payloadBytes, token)
+	if err != nil {
+		return fmt.Errorf("GraphQL request failed after retries: %w", err)
+	}
+	defer closeBody(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GraphQL query failed with status %s: %s", resp.Status, body)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode GraphQL result: %w", err)
+	}
+	if errs := result.errors(); len(errs) > 0 {
+		messages := make([]string, 0, len(errs))
+		for _, e := range errs {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("GraphQL query returned errors: %s", strings.Join(messages, "; "))
+	}
+	return nil
+}
+
+// artifactQueryDocument builds the GraphQL query for CoreArtifact, only the filters set in the query are declared
+// as variables, so Infrahub does not filter on empty values
+func artifactQueryDocument(query domain.ArtifactQuery) (string, map[string]any) {
+	type filter struct {
+		name, graphQLType string
+		value             any
+		set               bool
+	}
+	filters := []filter{
+		{"name__value", "String", query.Name, query.Name != ""},
+		{"object__ids", "[ID]", query.ObjectIDs, len(query.ObjectIDs) > 0},
+		{"status__values", "[String]", query.Statuses, len(query.Statuses) > 0},
+		{"definition__name__value", "String", query.Definition, query.Definition != ""},
+	}
+
+	declarations := []string{"$offset: Int", "$limit: Int"}
+	arguments := []string{"offset: $offset", "limit: $limit"}
+	variables := map[string]any{}
+	for _, f := range filters {
+		if !f.set {
+			continue
+		}
+		declarations = append(declarations, fmt.Sprintf("$%s: %s", f.name, f.graphQLType))
+		arguments = append(arguments, fmt.Sprintf("%s: $%s", f.name, f.name))
+		variables[f.name] = f.value
+	}
+
+	document := fmt.Sprintf(
+		"query VidraArtifacts(%s) { CoreArtifact(%s) { count edges { node { id name { value } storage_id { value } checksum { value } } } } }",
+		strings.Join(declarations, ", "), strings.Join(arguments, ", "))
+	return document, variables
+}
@@ -0,0 +1,139 @@
+package infrahub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/infrahub-operator/vidra/internal/domain"
+)
+
+var _ = Describe("GraphQL artifact query", func() {
+	var (
+		server   *httptest.Server
+		requests []graphQLPayload
+		paths    []string
+		total    int
+		token    = domain.Token{Value: "token"}
+	)
+
+	BeforeEach(func() {
+		requests, paths, total = nil, nil, 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload graphQLPayload
+			Expect(json.NewDecoder(r.Body).Decode(&payload)).To(Succeed())
+			requests = append(requests, payload)
+			paths = append(paths, r.URL.RequestURI())
+
+			offset := int(payload.Variables["offset"].(float64))
+			limit := int(payload.Variables["limit"].(float64))
+			if strings.Contains(payload.Query, "CoreStandardGroup") {
+				groups := []map[string]any{}
+				if payload.Variables["group"] == "leafs" {
+					members := []map[string]any{}
+					for i := offset; i < min(offset+limit, 3); i++ {
+						members = append(members, map[string]any{"node": map[string]any{"id": fmt.Sprintf("device-%d", i)}})
+					}
+					groups = append(groups, map[string]any{"node": map[string]any{"members": map[string]any{"count": 3, "edges": members}}})
+				}
+				_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"CoreStandardGroup": map[string]any{"edges": groups}}})
+				return
+			}
+			edges := []map[string]any{}
+			for i := offset; i < min(offset+limit, total); i++ {
+				edges = append(edges, map[string]any{"node": map[string]any{
+					"id":         fmt.Sprintf("artifact-%d", i),
+					"name":       map[string]string{"value": fmt.Sprintf("manifest-%d", i)},
+					"storage_id": map[string]string{"value": fmt.Sprintf("storage-%d", i)},
+					"checksum":   map[string]string{"value": fmt.Sprintf("checksum-%d", i)},
+				}})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]any{"CoreArtifact": map[string]any{"count": total, "edges": edges}},
+			})
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should fetch all pages of artifacts", func() {
+		total = 5
+		artifacts, err := NewClient().QueryArtifacts(context.Background(), server.URL,
+			domain.ArtifactQuery{Name: "manifest", Branch: "dev", PageSize: 2}, token)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*artifacts).To(HaveLen(5))
+		Expect((*artifacts)[4]).To(Equal(domain.Artifact{ID: "artifact-4", StorageID: "storage-4", Checksum: "checksum-4", Name: "manifest-4"}))
+
+		Expect(requests).To(HaveLen(3))
+		Expect(paths[0]).To(Equal("/graphql/dev"))
+		// The name selectors match on the name, so it has to be requested
+		Expect(requests[0].Query).To(ContainSubstring("node { id name { value } storage_id { value } checksum { value } }"))
+		Expect(requests[2].Variables).To(HaveKeyWithValue("offset", BeNumerically("==", 4)))
+	})
+
+	It("should send only the filters that are set", func() {
+		_, err := NewClient().QueryArtifacts(context.Background(), server.URL, domain.ArtifactQuery{
+			Name:       "manifest",
+			ObjectIDs:  []string{"device-1"},
+			Statuses:   []string{"Ready"},
+			Definition: "webserver",
+		}, token)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requests).To(HaveLen(1))
+		Expect(paths[0]).To(Equal("/graphql/main"))
+		Expect(requests[0].Query).To(ContainSubstring("CoreArtifact(offset: $offset, limit: $limit, name__value: $name__value, " +
+			"object__ids: $object__ids, status__values: $status__values, definition__name__value: $definition__name__value)"))
+		Expect(requests[0].Variables).To(HaveKeyWithValue("object__ids", ConsistOf("device-1")))
+		Expect(requests[0].Variables).To(HaveKeyWithValue("status__values", ConsistOf("Ready")))
+
+		requests = nil
+		_, err = NewClient().QueryArtifacts(context.Background(), server.URL, domain.ArtifactQuery{Name: "manifest"}, token)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requests[0].Query).ToNot(ContainSubstring("object__ids"))
+		Expect(requests[0].Variables).To(HaveKeyWithValue("limit", BeNumerically("==", defaultPageSize)))
+	})
+
+	It("should limit the artifacts to the members of the target group", func() {
+		total = 1
+		_, err := NewClient().QueryArtifacts(context.Background(), server.URL, domain.ArtifactQuery{
+			Definition:  "leaf_config",
+			TargetGroup: "leafs",
+			ObjectIDs:   []string{"device-2", "device-7"},
+			PageSize:    2,
+		}, token)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requests).To(HaveLen(3))
+		Expect(requests[0].Variables).To(HaveKeyWithValue("group", "leafs"))
+		Expect(requests[2].Variables).To(HaveKeyWithValue("object__ids", ConsistOf("device-2")))
+	})
+
+	It("should match nothing if the target group has no matching members", func() {
+		artifacts, err := NewClient().QueryArtifacts(context.Background(), server.URL, domain.ArtifactQuery{
+			TargetGroup: "leafs",
+			ObjectIDs:   []string{"device-7"},
+		}, token)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*artifacts).To(BeEmpty())
+		Expect(requests).To(HaveLen(1))
+
+		_, err = NewClient().QueryArtifacts(context.Background(), server.URL, domain.ArtifactQuery{TargetGroup: "spines"}, token)
+		Expect(err).To(MatchError(ContainSubstring(`target group "spines" not found`)))
+	})
+
+	It("should return the errors of the GraphQL response", func() {
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"data": null, "errors": [{"message": "Unknown argument"}]}`))
+		})
+
+		_, err := NewClient().QueryArtifacts(context.Background(), server.URL, domain.ArtifactQuery{Name: "manifest"}, token)
+		Expect(err).To(MatchError(ContainSubstring("GraphQL query returned errors: Unknown argument")))
+	})
+})
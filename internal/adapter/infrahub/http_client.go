@@ -0,0 +1,136 @@
+package infrahub
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/infrahub-operator/vidra/internal/domain"
+)
+
+const (
+	// dialTimeout limits establishing the TCP connection to Infrahub or the proxy
+	dialTimeout = 10 * time.Second
+	// tlsHandshakeTimeout limits the TLS handshake with Infrahub
+	tlsHandshakeTimeout = 10 * time.Second
+	// responseHeaderTimeout limits the wait for the response headers of Infrahub
+	responseHeaderTimeout = 30 * time.Second
+	// idleConnTimeout closes pooled connections that are not reused
+	idleConnTimeout = 90 * time.Second
+)
+
+// httpClientPool holds one HTTP client per distinct connection config, so connections to Infrahub are reused
+type httpClientPool struct {
+	mu sync.Mutex
+	// requestTimeout limits a request including reading the response body
+	requestTimeout time.Duration
+	clients        map[[sha256.Size]byte]*http.Client
+}
+
+func newHTTPClientPool(requestTimeout time.Duration) *httpClientPool {
+	return &httpClientPool{requestTimeout: requestTimeout, clients: map[[sha256.Size]byte]*http.Client{}}
+}
+
+// get returns the pooled HTTP client of the connection, creating it if needed
+func (p *httpClientPool) get(connection domain.Connection) (*http.Client, error) {
+	key := connectionKey(connection)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if client, ok := p.clients[key]; ok {
+		return client, nil
+	}
+	client, err := newHTTPClient(connection, p.requestTimeout)
+	if err != nil {
+		return nil, err
+	}
+	p.clients[key] = client
+	return client, nil
+}
+
+// connectionKey hashes the connection config, the hash avoids keeping the client key in the map key
+func connectionKey(connection domain.Connection) [sha256.Size]byte {
+	h := sha256.New()
+	for _, field := range [][]byte{
+		connection.CABundle,
+		connection.ClientCert,
+		connection.ClientKey,
+		[]byte(connection.ProxyURL),
+		[]byte(fmt.Sprint(connection.InsecureSkipVerify)),
+	} {
+		_, _ = fmt.Fprintf(h, "%d:", len(field))
+		_, _ = h.Write(field)
+	}
+	var key [sha256.Size]byte
+	copy(key[:], h.Sum(nil))
+	return key
+}
+
+// newHTTPClient builds an HTTP client with the TLS and proxy settings of the connection
+func newHTTPClient(connection domain.Connection, requestTimeout time.Duration) (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: connection.InsecureSkipVerify, //nolint:gosec // explicitly requested for labs
+	}
+
+	if len(connection.CABundle) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(connection.CABundle) {
+			return nil, fmt.Errorf("CA bundle contains no PEM encoded certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if len(connection.ClientCert) > 0 || len(connection.ClientKey) > 0 {
+		cert, err := tls.X509KeyPair(connection.ClientCert, connection.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if connection.ProxyURL != "" {
+		proxyURL, err := url.Parse(connection.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	transport := &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           (&net.Dialer{Timeout: dialTimeout, KeepAlive: 30 * time.Second}).DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   tlsHandshakeTimeout,
+		ResponseHeaderTimeout: responseHeaderTimeout,
+		IdleConnTimeout:       idleConnTimeout,
+		MaxIdleConnsPerHost:   10,
+		ForceAttemptHTTP2:     true,
+	}
+	return &http.Client{Transport: transport, Timeout: requestTimeout}, nil
+}
+
+// WithConnection returns a client sending its requests with the pooled HTTP client of the connection
+func (c *infrahubClient) WithConnection(connection domain.Connection) (domain.InfrahubClient, error) {
+	httpClient, err := c.httpClients.get(connection)
+	if err != nil {
+		return nil, err
+	}
+	return &infrahubClient{
+		options:     c.options,
+		tokens:      c.tokens,
+		httpClients: c.httpClients,
+		limiters:    c.limiters,
+		httpClient:  httpClient,
+	}, nil
+}
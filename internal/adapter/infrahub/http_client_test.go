@@ -0,0 +1,123 @@
+package infrahub
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/infrahub-operator/vidra/internal/domain"
+)
+
+// testClientCert returns a self-signed PEM encoded client certificate and key
+func testClientCert() ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "vidra"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).ToNot(HaveOccurred())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).ToNot(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+var _ = Describe("Infrahub connections", func() {
+	queryHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"CoreArtifact": {"edges": []}}}`))
+	})
+	token := domain.Token{Value: "token"}
+
+	It("should verify Infrahub with the CA bundle of the connection", func() {
+		server := httptest.NewTLSServer(queryHandler)
+		defer server.Close()
+		caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+		_, err := NewClient().RunQuery(context.Background(), "query", server.URL, "artifact", "main", "", token)
+		Expect(err).To(HaveOccurred())
+
+		client, err := NewClient().WithConnection(domain.Connection{CABundle: caBundle})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.RunQuery(context.Background(), "query", server.URL, "artifact", "main", "", token)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should skip the verification if requested", func() {
+		server := httptest.NewTLSServer(queryHandler)
+		defer server.Close()
+
+		client, err := NewClient().WithConnection(domain.Connection{InsecureSkipVerify: true})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.RunQuery(context.Background(), "query", server.URL, "artifact", "main", "", token)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should authenticate with the client certificate of the connection", func() {
+		cert, key := testClientCert()
+		clientCAs := x509.NewCertPool()
+		Expect(clientCAs.AppendCertsFromPEM(cert)).To(BeTrue())
+
+		server := httptest.NewUnstartedServer(queryHandler)
+		server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+		server.StartTLS()
+		defer server.Close()
+
+		client, err := NewClient().WithConnection(domain.Connection{InsecureSkipVerify: true, ClientCert: cert, ClientKey: key})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.RunQuery(context.Background(), "query", server.URL, "artifact", "main", "", token)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should send the requests through the proxy of the connection", func() {
+		var proxied atomic.Int32
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied.Add(1)
+			Expect(r.URL.Host).To(Equal("infrahub.internal"))
+			queryHandler(w, r)
+		}))
+		defer proxy.Close()
+
+		client, err := NewClient().WithConnection(domain.Connection{ProxyURL: proxy.URL})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = client.RunQuery(context.Background(), "query", "http://infrahub.internal", "artifact", "main", "", token)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(proxied.Load()).To(Equal(int32(1)))
+	})
+
+	It("should reuse the HTTP client of the same connection config", func() {
+		pool := newHTTPClientPool(time.Minute)
+		first, err := pool.get(domain.Connection{ProxyURL: "http://proxy:3128"})
+		Expect(err).ToNot(HaveOccurred())
+		second, err := pool.get(domain.Connection{ProxyURL: "http://proxy:3128"})
+		Expect(err).ToNot(HaveOccurred())
+		other, err := pool.get(domain.Connection{ProxyURL: "http://proxy:3128", InsecureSkipVerify: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(second).To(BeIdenticalTo(first))
+		Expect(other).ToNot(BeIdenticalTo(first))
+		Expect(first.Timeout).To(Equal(time.Minute))
+	})
+
+	It("should reject invalid CA bundles", func() {
+		_, err := NewClient().WithConnection(domain.Connection{CABundle: []byte("not a certificate")})
+		Expect(err).To(MatchError(ContainSubstring("CA bundle contains no PEM encoded certificate")))
+	})
+})
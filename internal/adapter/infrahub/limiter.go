@@ -0,0 +1,104 @@
+package infrahub
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// requestQueueWait measures how long requests wait for the rate limiter and a free in-flight slot
+var requestQueueWait = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "vidra_infrahub_request_queue_wait_seconds",
+	Help:    "Time requests to an Infrahub instance wait for the rate limiter and a free in-flight slot",
+	Buckets: []float64{0.001, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+}, []string{"instance"})
+
+func init() {
+	metrics.Registry.MustRegister(requestQueueWait)
+}
+
+// instanceLimiter bounds the request rate and the requests in flight to one Infrahub instance
+type instanceLimiter struct {
+	instance string
+	// rate is nil if the request rate is not limited
+	rate *rate.Limiter
+	// inFlight holds a slot per running request, it is nil if the requests in flight are not limited
+	inFlight chan struct{}
+}
+
+// acquire waits for a free in-flight slot and then for the rate limiter. The returned release frees the slot.
+func (l *instanceLimiter) acquire(ctx context.Context) (func(), error) {
+	start := time.Now()
+	defer func() {
+		requestQueueWait.WithLabelValues(l.instance).Observe(time.Since(start).Seconds())
+	}()
+
+	release := func() {}
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		var once sync.Once
+		release = func() { once.Do(func() { <-l.inFlight }) }
+	}
+	if l.rate != nil {
+		if err := l.rate.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+// limiterPool holds the limiter of every Infrahub instance, shared by all clients so the limits apply to all syncs
+type limiterPool struct {
+	mu       sync.Mutex
+	options  ClientOptions
+	limiters map[string]*instanceLimiter
+}
+
+func newLimiterPool(options ClientOptions) *limiterPool {
+	return &limiterPool{options: options, limiters: map[string]*instanceLimiter{}}
+}
+
+// get returns the limiter of the Infrahub instance serving the request URL, identified by scheme and host
+func (p *limiterPool) get(requestURL string) *instanceLimiter {
+	instance := requestURL
+	if u, err := url.Parse(requestURL); err == nil {
+		instance = u.Scheme + "://" + u.Host
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if limiter, ok := p.limiters[instance]; ok {
+		return limiter
+	}
+	limiter := &instanceLimiter{instance: instance}
+	if p.options.RateLimit > 0 {
+		limiter.rate = rate.NewLimiter(rate.Limit(p.options.RateLimit), max(p.options.RateBurst, 1))
+	}
+	if p.options.MaxInFlight > 0 {
+		limiter.inFlight = make(chan struct{}, p.options.MaxInFlight)
+	}
+	p.limiters[instance] = limiter
+	return limiter
+}
+
+// releaseOnClose frees the in-flight slot of a request once its response body is closed
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	defer r.release()
+	return r.ReadCloser.Close()
+}
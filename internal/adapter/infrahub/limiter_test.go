@@ -0,0 +1,144 @@
+package infrahub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/infrahub-operator/vidra/internal/domain"
+)
+
+var _ = Describe("Request limits", func() {
+	var (
+		server   *httptest.Server
+		inFlight atomic.Int32
+		peak     atomic.Int32
+		token    = domain.Token{Value: "token"}
+	)
+
+	BeforeEach(func() {
+		inFlight.Store(0)
+		peak.Store(0)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				old := peak.Load()
+				if current <= old || peak.CompareAndSwap(old, current) {
+					break
+				}
+			}
+			time.Sleep(50 * time.Millisecond)
+			_, _ = w.Write([]byte(`{"data": {"CoreArtifact": {"edges": []}}}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	// runQueries sends n queries concurrently and waits for all of them
+	runQueries := func(client domain.InfrahubClient, n int) {
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, err := client.RunQuery(context.Background(), "query", server.URL, "artifact", "main", "", token)
+				Expect(err).ToNot(HaveOccurred())
+			}()
+		}
+		wg.Wait()
+	}
+
+	It("should limit the requests in flight per Infrahub instance", func() {
+		client := NewClientWithOptions(ClientOptions{MaxInFlight: 2, RequestTimeout: time.Second})
+		runQueries(client, 6)
+		Expect(peak.Load()).To(Equal(int32(2)))
+	})
+
+	It("should share the limits between the connections of a client", func() {
+		client := NewClientWithOptions(ClientOptions{MaxInFlight: 1, RequestTimeout: time.Second})
+		proxied, err := client.WithConnection(domain.Connection{InsecureSkipVerify: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		var wg sync.WaitGroup
+		for _, c := range []domain.InfrahubClient{client, proxied, client, proxied} {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, err := c.RunQuery(context.Background(), "query", server.URL, "artifact", "main", "", token)
+				Expect(err).ToNot(HaveOccurred())
+			}()
+		}
+		wg.Wait()
+		Expect(peak.Load()).To(Equal(int32(1)))
+	})
+
+	It("should limit the request rate per Infrahub instance", func() {
+		client := NewClientWithOptions(ClientOptions{RateLimit: 10, RateBurst: 1, RequestTimeout: time.Second})
+		start := time.Now()
+		runQueries(client, 4)
+		Expect(time.Since(start)).To(BeNumerically(">=", 300*time.Millisecond))
+	})
+
+	It("should hold the in-flight slot of a download until its body is closed", func() {
+		client := NewClientWithOptions(ClientOptions{MaxInFlight: 1, RequestTimeout: time.Second})
+		body, err := client.DownloadArtifact(context.Background(), server.URL, "artifact", "main", "", token)
+		Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, err = client.DownloadArtifact(ctx, server.URL, "artifact", "main", "", token)
+		Expect(err).To(MatchError(ContainSubstring(context.DeadlineExceeded.Error())))
+
+		Expect(body.(io.Closer).Close()).To(Succeed())
+		_, err = client.DownloadArtifact(context.Background(), server.URL, "artifact", "main", "", token)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should log in again on a 401 while all slots are taken", func() {
+		var logins atomic.Int32
+		authServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api/auth/login" {
+				_ = json.NewEncoder(w).Encode(map[string]string{"access_token": fmt.Sprintf("token-%d", logins.Add(1))})
+				return
+			}
+			// Only the token of the second login is accepted
+			if r.Header.Get("Authorization") != "Bearer token-2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"data": {"CoreArtifact": {"edges": []}}}`))
+		}))
+		defer authServer.Close()
+
+		client := NewClientWithOptions(ClientOptions{MaxInFlight: 1, RequestTimeout: time.Second})
+		first, err := client.Login(context.Background(), authServer.URL, domain.Credentials{Username: "user", Password: "pass"})
+		Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_, err = client.RunQuery(ctx, "query", authServer.URL, "artifact", "main", "", first)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(logins.Load()).To(Equal(int32(2)))
+	})
+
+	It("should measure the queue wait time per instance", func() {
+		client := NewClientWithOptions(ClientOptions{MaxInFlight: 1, RequestTimeout: time.Second})
+		runQueries(client, 2)
+		Expect(testutil.CollectAndCount(requestQueueWait)).To(BeNumerically(">=", 1))
+	})
+})
@@ -55,5 +55,74 @@ func CreateArtifactsFromAPIResponse(apiResponse artifactIDQueryResult) []domain.
 }
 
 type LoginResponse struct {
-	Token string `json:"access_token"`
+	Token        string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type graphQLPayload struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+// artifactPage is one page of CoreArtifact with the total count of matching artifacts
+type artifactPage struct {
+	Count int `json:"count"`
+	Edges []struct {
+		Node struct {
+			ID   string `json:"id"`
+			Name struct {
+				Value string `json:"value"`
+			} `json:"name"`
+			StorageID struct {
+				Value string `json:"value"`
+			} `json:"storage_id"`
+			Checksum struct {
+				Value string `json:"value"`
+			} `json:"checksum"`
+		} `json:"node"`
+	} `json:"edges"`
+}
+
+// graphQLResult is a decoded GraphQL response exposing the errors of the response
+type graphQLResult interface {
+	errors() []graphQLError
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+type graphQLErrors struct {
+	Errors []graphQLError `json:"errors"`
+}
+
+func (e *graphQLErrors) errors() []graphQLError {
+	return e.Errors
+}
+
+type artifactGraphQLResult struct {
+	Data struct {
+		CoreArtifact artifactPage `json:"CoreArtifact"`
+	} `json:"data"`
+	graphQLErrors
+}
+
+type groupMembersGraphQLResult struct {
+	Data struct {
+		CoreStandardGroup struct {
+			Edges []struct {
+				Node struct {
+					Members struct {
+						Count int `json:"count"`
+						Edges []struct {
+							Node struct {
+								ID string `json:"id"`
+							} `json:"node"`
+						} `json:"edges"`
+					} `json:"members"`
+				} `json:"node"`
+			} `json:"edges"`
+		} `json:"CoreStandardGroup"`
+	} `json:"data"`
+	graphQLErrors
 }
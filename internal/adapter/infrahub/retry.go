@@ -0,0 +1,164 @@
+package infrahub
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/infrahub-operator/vidra/internal/domain"
+)
+
+const (
+	// DefaultMaxRetries is the number of retries of a failed request to Infrahub
+	DefaultMaxRetries = 4
+	// DefaultRetryBackoff is the delay before the first retry, it doubles with every retry
+	DefaultRetryBackoff = 200 * time.Millisecond
+	// DefaultRequestTimeout limits a single request to Infrahub including reading the response body
+	DefaultRequestTimeout = 2 * time.Minute
+	// DefaultRateLimit is the number of requests per second to one Infrahub instance
+	DefaultRateLimit = 20
+	// DefaultRateBurst is the number of requests to one Infrahub instance allowed above the rate limit at once
+	DefaultRateBurst = 40
+	// DefaultMaxInFlight is the number of concurrent requests to one Infrahub instance
+	DefaultMaxInFlight = 10
+	// maxRetryAfter caps the delay requested by Infrahub with Retry-After
+	maxRetryAfter = time.Minute
+)
+
+// ClientOptions configures the retries, timeouts and limits of the requests to Infrahub
+type ClientOptions struct {
+	// MaxRetries is the number of retries after the first attempt, 0 disables retries
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, it doubles with every retry
+	RetryBackoff time.Duration
+	// RequestTimeout limits a single attempt including reading the response body
+	RequestTimeout time.Duration
+	// RateLimit is the number of requests per second to one Infrahub instance, 0 disables the limit
+	RateLimit float64
+	// RateBurst is the number of requests allowed above the rate limit at once
+	RateBurst int
+	// MaxInFlight is the number of concurrent requests to one Infrahub instance, 0 disables the limit.
+	// A request is in flight until its response body is closed.
+	MaxInFlight int
+}
+
+// DefaultClientOptions returns the default retries, timeouts and limits
+func DefaultClientOptions() ClientOptions {
+	return ClientOptions{
+		MaxRetries:     DefaultMaxRetries,
+		RetryBackoff:   DefaultRetryBackoff,
+		RequestTimeout: DefaultRequestTimeout,
+		RateLimit:      DefaultRateLimit,
+		RateBurst:      DefaultRateBurst,
+		MaxInFlight:    DefaultMaxInFlight,
+	}
+}
+
+// do sends the request and retries it on network errors, 5xx and 429 responses until the retries are exhausted
+// or the context is cancelled. If a token is given, a 401 is answered with one new login if the token can be renewed.
+// Every attempt waits for the rate limiter and a free in-flight slot of the Infrahub instance.
+// The response of the last attempt is returned, the caller must close its body.
+func (c *infrahubClient) do(ctx context.Context, method, url string, body []byte, token *domain.Token) (*http.Response, error) {
+	// reauthenticated limits the login after a rejected token to one per request
+	var reauthenticated bool
+	backoff := c.options.RetryBackoff
+	limiter := c.limiters.get(url)
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Accept", "application/json")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if token != nil {
+			setAuthHeader(req, *token)
+		}
+
+		release, err := limiter.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			release()
+		} else {
+			resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+		}
+		if ctx.Err() != nil {
+			if err == nil {
+				closeBody(resp)
+			}
+			return nil, ctx.Err()
+		}
+		if err == nil && resp.StatusCode == http.StatusUnauthorized && token != nil && !reauthenticated {
+			// The login needs an in-flight slot itself, so the slot of the rejected request is freed first
+			rejected, _ := io.ReadAll(resp.Body)
+			closeBody(resp)
+			resp.Body = io.NopCloser(bytes.NewReader(rejected))
+			if newToken, rerr := c.reauthenticate(ctx, *token); rerr == nil {
+				*token, reauthenticated = newToken, true
+				attempt--
+				continue
+			}
+		}
+		if err == nil && !retryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+		if attempt >= c.options.MaxRetries {
+			return resp, err
+		}
+
+		delay := backoff
+		if err == nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				delay = retryAfter
+			}
+			closeBody(resp)
+		}
+		backoff *= 2
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryableStatus reports whether a request failed temporarily and should be retried
+func retryableStatus(status int) bool {
+	return status >= http.StatusInternalServerError || status == http.StatusTooManyRequests
+}
+
+// parseRetryAfter reads the delay of a Retry-After header in seconds or as HTTP date, capped to maxRetryAfter
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		delay = time.Until(date)
+	} else {
+		return 0, false
+	}
+	return min(max(delay, 0), maxRetryAfter), true
+}
+
+// closeBody drains and closes the body of a response, so the connection can be reused
+func closeBody(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if err := resp.Body.Close(); err != nil {
+		fmt.Printf("warning: failed to close response body: %v\n", err)
+	}
+}
@@ -0,0 +1,129 @@
+package infrahub
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/infrahub-operator/vidra/internal/domain"
+)
+
+var _ = Describe("Request retries", func() {
+	var (
+		server   *httptest.Server
+		attempts atomic.Int32
+		token    = domain.Token{Value: "token"}
+		options  = ClientOptions{MaxRetries: 2, RetryBackoff: 10 * time.Millisecond, RequestTimeout: time.Second}
+	)
+
+	// serve starts a server answering every request with handle
+	serve := func(handle func(w http.ResponseWriter, attempt int32)) {
+		attempts.Store(0)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handle(w, attempts.Add(1))
+		}))
+	}
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should retry server errors until the request succeeds", func() {
+		serve(func(w http.ResponseWriter, attempt int32) {
+			if attempt < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			_, _ = w.Write([]byte(`{"data": {"CoreArtifact": {"edges": []}}}`))
+		})
+
+		_, err := NewClientWithOptions(options).RunQuery(context.Background(), "query", server.URL, "artifact", "main", "", token)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(attempts.Load()).To(Equal(int32(3)))
+	})
+
+	It("should stop after the configured number of retries", func() {
+		serve(func(w http.ResponseWriter, _ int32) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+		_, err := NewClientWithOptions(options).RunQuery(context.Background(), "query", server.URL, "artifact", "main", "", token)
+		Expect(err).To(MatchError(ContainSubstring("query failed with status 503")))
+		Expect(attempts.Load()).To(Equal(int32(3)))
+	})
+
+	It("should not retry client errors", func() {
+		serve(func(w http.ResponseWriter, _ int32) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		_, err := NewClientWithOptions(options).DownloadArtifact(context.Background(), server.URL, "artifact", "main", "", token)
+		Expect(err).To(MatchError(ContainSubstring("last status code: 404")))
+		Expect(attempts.Load()).To(Equal(int32(1)))
+	})
+
+	It("should wait as long as requested by Retry-After of a 429", func() {
+		serve(func(w http.ResponseWriter, attempt int32) {
+			if attempt == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			_, _ = w.Write([]byte("artifact-content"))
+		})
+
+		start := time.Now()
+		_, err := NewClientWithOptions(options).DownloadArtifact(context.Background(), server.URL, "artifact", "main", "", token)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(time.Since(start)).To(BeNumerically(">=", time.Second))
+		Expect(attempts.Load()).To(Equal(int32(2)))
+	})
+
+	It("should give up if the context is cancelled", func() {
+		serve(func(w http.ResponseWriter, _ int32) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		slowOptions := options
+		slowOptions.RetryBackoff = time.Hour
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := NewClientWithOptions(slowOptions).RunQuery(ctx, "query", server.URL, "artifact", "main", "", token)
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+
+	It("should abort requests exceeding the request timeout", func() {
+		serve(func(w http.ResponseWriter, _ int32) {
+			time.Sleep(500 * time.Millisecond)
+		})
+		fastOptions := ClientOptions{MaxRetries: 0, RetryBackoff: time.Millisecond, RequestTimeout: 50 * time.Millisecond}
+
+		_, err := NewClientWithOptions(fastOptions).RunQuery(context.Background(), "query", server.URL, "artifact", "main", "", token)
+		Expect(err).To(MatchError(ContainSubstring("Client.Timeout exceeded")))
+		Expect(attempts.Load()).To(Equal(int32(1)))
+	})
+
+	It("should parse Retry-After in seconds and as HTTP date", func() {
+		delay, ok := parseRetryAfter("5")
+		Expect(ok).To(BeTrue())
+		Expect(delay).To(Equal(5 * time.Second))
+
+		delay, ok = parseRetryAfter(time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat))
+		Expect(ok).To(BeTrue())
+		Expect(delay).To(BeNumerically("~", 30*time.Second, 2*time.Second))
+
+		delay, ok = parseRetryAfter("3600")
+		Expect(ok).To(BeTrue())
+		Expect(delay).To(Equal(maxRetryAfter))
+
+		_, ok = parseRetryAfter("soon")
+		Expect(ok).To(BeFalse())
+	})
+})
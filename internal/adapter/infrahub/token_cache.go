@@ -0,0 +1,209 @@
+package infrahub
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infrahub-operator/vidra/internal/domain"
+)
+
+const (
+	// tokenRefreshMargin is how long before their expiry tokens are refreshed
+	tokenRefreshMargin = time.Minute
+	// defaultTokenLifetime is assumed for tokens without a readable expiry
+	defaultTokenLifetime = 15 * time.Minute
+)
+
+// tokenKey identifies the tokens of a user of an Infrahub instance, the password is only kept as hash
+type tokenKey struct {
+	apiURL       string
+	username     string
+	passwordHash string
+}
+
+// cachedToken holds the tokens of a user, mu serializes logins and refreshes of the same user
+type cachedToken struct {
+	mu            sync.Mutex
+	apiURL        string
+	username      string
+	password      string
+	accessToken   string
+	accessExpiry  time.Time
+	refreshToken  string
+	refreshExpiry time.Time
+}
+
+// tokenCache caches the tokens per Infrahub API URL and credentials, it is safe for concurrent use
+type tokenCache struct {
+	mu      sync.Mutex
+	entries map[tokenKey]*cachedToken
+	// byAccessToken finds the entry of an access token rejected by Infrahub
+	byAccessToken map[string]*cachedToken
+}
+
+// entry returns the cache entry of the credentials, creating it if needed
+func (t *tokenCache) entry(apiURL, username, password string) *cachedToken {
+	hash := sha256.Sum256([]byte(password))
+	key := tokenKey{apiURL: apiURL, username: username, passwordHash: hex.EncodeToString(hash[:])}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.entries == nil {
+		t.entries = map[tokenKey]*cachedToken{}
+		t.byAccessToken = map[string]*cachedToken{}
+	}
+	entry, ok := t.entries[key]
+	if !ok {
+		entry = &cachedToken{apiURL: apiURL, username: username, password: password}
+		t.entries[key] = entry
+	}
+	return entry
+}
+
+// lookup returns the cache entry an access token was issued for
+func (t *tokenCache) lookup(accessToken string) *cachedToken {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.byAccessToken[accessToken]
+}
+
+// store sets the access token of the entry, the caller must hold the lock of the entry
+func (t *tokenCache) store(entry *cachedToken, accessToken string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.byAccessToken, entry.accessToken)
+	entry.accessToken = accessToken
+	entry.accessExpiry = tokenExpiry(accessToken)
+	t.byAccessToken[accessToken] = entry
+}
+
+// Login returns the API token of the credentials or a cached access token of the user. Tokens about to expire
+// are refreshed with the refresh token, a new login is only done if there is no valid refresh token.
+func (c *infrahubClient) Login(ctx context.Context, apiURL string, credentials domain.Credentials) (domain.Token, error) {
+	if credentials.APIToken != "" {
+		return domain.Token{Value: credentials.APIToken, APIToken: true}, nil
+	}
+	accessToken, err := c.accessToken(ctx, apiURL, credentials.Username, credentials.Password)
+	if err != nil {
+		return domain.Token{}, err
+	}
+	return domain.Token{Value: accessToken}, nil
+}
+
+// accessToken returns a valid access token of the user
+func (c *infrahubClient) accessToken(ctx context.Context, apiURL, username, password string) (string, error) {
+	entry := c.tokens.entry(apiURL, username, password)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	now := time.Now()
+	if entry.accessToken != "" && now.Add(tokenRefreshMargin).Before(entry.accessExpiry) {
+		return entry.accessToken, nil
+	}
+	if entry.refreshToken != "" && now.Add(tokenRefreshMargin).Before(entry.refreshExpiry) {
+		if accessToken, err := c.refresh(ctx, apiURL, entry.refreshToken); err == nil {
+			c.tokens.store(entry, accessToken)
+			return accessToken, nil
+		}
+	}
+	return c.loginEntry(ctx, entry)
+}
+
+// reauthenticate logs in again after Infrahub rejected the access token with a 401, API tokens can not be renewed
+func (c *infrahubClient) reauthenticate(ctx context.Context, token domain.Token) (domain.Token, error) {
+	if token.APIToken {
+		return domain.Token{}, fmt.Errorf("API token was rejected")
+	}
+	entry := c.tokens.lookup(token.Value)
+	if entry == nil {
+		return domain.Token{}, fmt.Errorf("access token was rejected and is not cached")
+	}
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	// Another request already logged in again
+	if entry.accessToken != token.Value {
+		return domain.Token{Value: entry.accessToken}, nil
+	}
+	accessToken, err := c.loginEntry(ctx, entry)
+	if err != nil {
+		return domain.Token{}, err
+	}
+	return domain.Token{Value: accessToken}, nil
+}
+
+// loginEntry logs in and caches the tokens, the caller must hold the lock of the entry
+func (c *infrahubClient) loginEntry(ctx context.Context, entry *cachedToken) (string, error) {
+	loginResp, err := c.login(ctx, entry.apiURL, entry.username, entry.password)
+	if err != nil {
+		return "", err
+	}
+	c.tokens.store(entry, loginResp.Token)
+	entry.refreshToken = loginResp.RefreshToken
+	entry.refreshExpiry = tokenExpiry(loginResp.RefreshToken)
+	return loginResp.Token, nil
+}
+
+// refresh requests a new access token with the refresh token
+func (c *infrahubClient) refresh(ctx context.Context, apiURL, refreshToken string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/auth/refresh", apiURL), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create refresh request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", refreshToken))
+
+	release, err := c.limiters.get(apiURL).acquire(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("refresh request failed: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			fmt.Printf("warning: failed to close response body: %v\n", cerr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("refresh failed with status %s: %s", resp.Status, body)
+	}
+
+	var refreshResp LoginResponse
+	if err := json.NewDecoder(resp.Body).Decode(&refreshResp); err != nil {
+		return "", fmt.Errorf("failed to decode refresh response: %w", err)
+	}
+	if refreshResp.Token == "" {
+		return "", fmt.Errorf("refresh response contains no access token")
+	}
+	return refreshResp.Token, nil
+}
+
+// tokenExpiry reads the expiry from the exp claim of a JWT, tokens without one are assumed to be valid for the default lifetime
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) == 3 {
+		if payload, err := base64.RawURLEncoding.DecodeString(parts[1]); err == nil {
+			var claims struct {
+				Exp int64 `json:"exp"`
+			}
+			if err := json.Unmarshal(payload, &claims); err == nil && claims.Exp > 0 {
+				return time.Unix(claims.Exp, 0)
+			}
+		}
+	}
+	return time.Now().Add(defaultTokenLifetime)
+}
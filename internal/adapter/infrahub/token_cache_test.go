@@ -0,0 +1,153 @@
+package infrahub
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/infrahub-operator/vidra/internal/domain"
+)
+
+// testJWT returns an unsigned JWT expiring after the given duration
+func testJWT(subject string, expiresIn time.Duration) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	payload := base64.RawURLEncoding.EncodeToString(
+		[]byte(fmt.Sprintf(`{"sub":%q,"exp":%d}`, subject, time.Now().Add(expiresIn).Unix())))
+	return header + "." + payload + ".signature"
+}
+
+var _ = Describe("Token cache", func() {
+	var (
+		server      *httptest.Server
+		logins      atomic.Int32
+		refreshes   atomic.Int32
+		accessTTL   time.Duration
+		validTokens sync.Map
+	)
+
+	BeforeEach(func() {
+		logins.Store(0)
+		refreshes.Store(0)
+		accessTTL = time.Hour
+		validTokens = sync.Map{}
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/auth/login":
+				n := logins.Add(1)
+				access := testJWT(fmt.Sprintf("access-%d", n), accessTTL)
+				validTokens.Store(access, true)
+				_ = json.NewEncoder(w).Encode(map[string]string{
+					"access_token":  access,
+					"refresh_token": testJWT("refresh", 24*time.Hour),
+				})
+			case "/api/auth/refresh":
+				n := refreshes.Add(1)
+				Expect(r.Header.Get("Authorization")).To(HavePrefix("Bearer "))
+				access := testJWT(fmt.Sprintf("refreshed-%d", n), time.Hour)
+				validTokens.Store(access, true)
+				_ = json.NewEncoder(w).Encode(map[string]string{"access_token": access})
+			default:
+				token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+				if r.Header.Get("X-INFRAHUB-KEY") == "api-token" {
+					token = "api-token"
+				}
+				if _, ok := validTokens.Load(token); !ok {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				_, _ = w.Write([]byte(`{"data": {"CoreArtifact": {"edges": []}}}`))
+			}
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should reuse the token of the same API URL and credentials", func() {
+		client := NewClient()
+		first, err := client.Login(context.Background(), server.URL, domain.Credentials{Username: "user", Password: "pass"})
+		Expect(err).ToNot(HaveOccurred())
+		second, err := client.Login(context.Background(), server.URL, domain.Credentials{Username: "user", Password: "pass"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(second).To(Equal(first))
+		Expect(logins.Load()).To(Equal(int32(1)))
+
+		_, err = client.Login(context.Background(), server.URL, domain.Credentials{Username: "other", Password: "pass"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(logins.Load()).To(Equal(int32(2)))
+	})
+
+	It("should refresh a token about to expire instead of logging in again", func() {
+		accessTTL = 30 * time.Second
+		client := NewClient()
+		first, err := client.Login(context.Background(), server.URL, domain.Credentials{Username: "user", Password: "pass"})
+		Expect(err).ToNot(HaveOccurred())
+
+		second, err := client.Login(context.Background(), server.URL, domain.Credentials{Username: "user", Password: "pass"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(second).ToNot(Equal(first))
+		Expect(logins.Load()).To(Equal(int32(1)))
+		Expect(refreshes.Load()).To(Equal(int32(1)))
+	})
+
+	It("should log in again if Infrahub rejects the cached token", func() {
+		client := NewClient()
+		token, err := client.Login(context.Background(), server.URL, domain.Credentials{Username: "user", Password: "pass"})
+		Expect(err).ToNot(HaveOccurred())
+		validTokens.Delete(token.Value)
+
+		_, err = client.RunQuery(context.Background(), "artifact_ids", server.URL, "artifact", "main", "", token)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(logins.Load()).To(Equal(int32(2)))
+
+		newToken, err := client.Login(context.Background(), server.URL, domain.Credentials{Username: "user", Password: "pass"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(newToken).ToNot(Equal(token))
+		Expect(logins.Load()).To(Equal(int32(2)))
+	})
+
+	It("should send API tokens as X-INFRAHUB-KEY without logging in", func() {
+		validTokens.Store("api-token", true)
+		client := NewClient()
+		token, err := client.Login(context.Background(), server.URL, domain.Credentials{Username: "user", Password: "pass", APIToken: "api-token"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).To(Equal(domain.Token{Value: "api-token", APIToken: true}))
+
+		_, err = client.RunQuery(context.Background(), "artifact_ids", server.URL, "artifact", "main", "", token)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(logins.Load()).To(BeZero())
+	})
+
+	It("should log in only once for concurrent reconciles", func() {
+		client := NewClient()
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, err := client.Login(context.Background(), server.URL, domain.Credentials{Username: "user", Password: "pass"})
+				Expect(err).ToNot(HaveOccurred())
+			}()
+		}
+		wg.Wait()
+		Expect(logins.Load()).To(Equal(int32(1)))
+	})
+
+	It("should read the expiry of JWTs", func() {
+		Expect(tokenExpiry(testJWT("user", time.Hour))).To(BeTemporally("~", time.Now().Add(time.Hour), time.Second))
+		Expect(tokenExpiry("opaque")).To(BeTemporally("~", time.Now().Add(defaultTokenLifetime), time.Second))
+	})
+})
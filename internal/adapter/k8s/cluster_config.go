@@ -0,0 +1,180 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ClusterKubeconfigKey is the key of the kubeconfig in the credentials Secret of a VidraCluster
+const ClusterKubeconfigKey = "kubeconfig"
+
+// RESTConfigForCluster builds the REST config of the VidraCluster from its credentials. The server, CA bundle and
+// TLS verification of the VidraCluster override the ones of the credentials.
+func RESTConfigForCluster(ctx context.Context, k8sClient client.Client, cluster *infrahubv1alpha1.VidraCluster) (*rest.Config, error) {
+	restConfig, _, err := restConfigForCluster(ctx, k8sClient, cluster)
+	return restConfig, err
+}
+
+// restConfigForCluster builds the REST config of the VidraCluster and returns the version of its spec and of the
+// objects the config was read from, a client is rebuilt once the version changes
+func restConfigForCluster(ctx context.Context, k8sClient client.Client, cluster *infrahubv1alpha1.VidraCluster) (*rest.Config, string, error) {
+	version := fmt.Sprintf("%d", cluster.Generation)
+
+	var restConfig *rest.Config
+	if cluster.Spec.CredentialsType == infrahubv1alpha1.ClusterCredentialsServiceAccountToken {
+		if cluster.Spec.TokenFile == "" {
+			return nil, "", fmt.Errorf("credentialsType ServiceAccountToken needs a tokenFile")
+		}
+		// client-go reads the file again once the kubelet rotated the projected token
+		restConfig = &rest.Config{BearerTokenFile: cluster.Spec.TokenFile}
+	} else {
+		ref := cluster.Spec.CredentialsSecretRef
+		if ref == nil {
+			return nil, "", fmt.Errorf("credentialsType %s needs a credentialsSecretRef", credentialsType(cluster))
+		}
+		name := types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}
+		secret := &v1.Secret{}
+		if err := k8sClient.Get(ctx, name, secret); err != nil {
+			return nil, "", fmt.Errorf("failed to get credentials Secret %s: %w", name, err)
+		}
+		version += "/" + secret.ResourceVersion
+
+		var err error
+		switch credentialsType(cluster) {
+		case infrahubv1alpha1.ClusterCredentialsToken:
+			restConfig, err = restConfigFromToken(secret)
+		case infrahubv1alpha1.ClusterCredentialsClientCertificate:
+			restConfig, err = restConfigFromClientCertificate(secret)
+		default:
+			restConfig, err = restConfigFromKubeconfig(secret, ref.Context)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("credentials Secret %s: %w", name, err)
+		}
+	}
+	restConfig.Host = cluster.Spec.Server
+
+	if cluster.Spec.CABundle != nil {
+		caBundle, caVersion, err := readCABundle(ctx, k8sClient, cluster.Spec.CABundle)
+		if err != nil {
+			return nil, "", err
+		}
+		restConfig.CAData = caBundle
+		restConfig.CAFile = ""
+		version += "/" + caVersion
+	}
+	if cluster.Spec.InsecureSkipVerify {
+		restConfig.Insecure = true
+		restConfig.CAData = nil
+		restConfig.CAFile = ""
+	}
+	return restConfig, version, nil
+}
+
+// credentialsType returns the credentials type of the VidraCluster, Kubeconfig if it is not set
+func credentialsType(cluster *infrahubv1alpha1.VidraCluster) infrahubv1alpha1.ClusterCredentialsType {
+	if cluster.Spec.CredentialsType == "" {
+		return infrahubv1alpha1.ClusterCredentialsKubeconfig
+	}
+	return cluster.Spec.CredentialsType
+}
+
+// restConfigFromKubeconfig builds the REST config from the context of the kubeconfig in the Secret, the current
+// context if it is empty
+func restConfigFromKubeconfig(secret *v1.Secret, contextName string) (*rest.Config, error) {
+	kubeConfigData := secret.Data[ClusterKubeconfigKey]
+	if len(kubeConfigData) == 0 {
+		return nil, fmt.Errorf("has no key %s", ClusterKubeconfigKey)
+	}
+	rawConfig, err := clientcmd.Load(kubeConfigData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	if contextName == "" {
+		contextName = rawConfig.CurrentContext
+	}
+	kubeContext, ok := rawConfig.Contexts[contextName]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig has no context %q", contextName)
+	}
+
+	// Plugins run binaries like aws or gke-gcloud-auth-plugin, which are not part of the operator image
+	if authInfo := rawConfig.AuthInfos[kubeContext.AuthInfo]; authInfo != nil {
+		if authInfo.Exec != nil {
+			return nil, fmt.Errorf("kubeconfig user %q uses the exec plugin %s which cannot run in the operator, use credentialsType Token, ClientCertificate or ServiceAccountToken instead",
+				kubeContext.AuthInfo, authInfo.Exec.Command)
+		}
+		if authInfo.AuthProvider != nil {
+			return nil, fmt.Errorf("kubeconfig user %q uses the auth provider %s which is not supported, use credentialsType Token, ClientCertificate or ServiceAccountToken instead",
+				kubeContext.AuthInfo, authInfo.AuthProvider.Name)
+		}
+	}
+
+	restConfig, err := clientcmd.NewNonInteractiveClientConfig(*rawConfig, contextName, &clientcmd.ConfigOverrides{}, nil).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST config from kubeconfig: %w", err)
+	}
+	return restConfig, nil
+}
+
+// restConfigFromToken builds the REST config from the bearer token and the optional CA of the Secret
+func restConfigFromToken(secret *v1.Secret) (*rest.Config, error) {
+	token := strings.TrimSpace(string(secret.Data[v1.ServiceAccountTokenKey]))
+	if token == "" {
+		return nil, fmt.Errorf("has no key %s", v1.ServiceAccountTokenKey)
+	}
+	return &rest.Config{
+		BearerToken:     token,
+		TLSClientConfig: rest.TLSClientConfig{CAData: secret.Data[v1.ServiceAccountRootCAKey]},
+	}, nil
+}
+
+// restConfigFromClientCertificate builds the REST config from the client certificate and the optional CA of the Secret
+func restConfigFromClientCertificate(secret *v1.Secret) (*rest.Config, error) {
+	cert, key := secret.Data[v1.TLSCertKey], secret.Data[v1.TLSPrivateKeyKey]
+	if len(cert) == 0 || len(key) == 0 {
+		return nil, fmt.Errorf("needs both %s and %s", v1.TLSCertKey, v1.TLSPrivateKeyKey)
+	}
+	return &rest.Config{
+		TLSClientConfig: rest.TLSClientConfig{CertData: cert, KeyData: key, CAData: secret.Data[v1.ServiceAccountRootCAKey]},
+	}, nil
+}
+
+// readCABundle reads the CA bundle from the referenced ConfigMap or Secret and returns the resource version of it
+func readCABundle(ctx context.Context, k8sClient client.Client, ref *infrahubv1alpha1.CABundleSource) ([]byte, string, error) {
+	key := ref.Key
+	if key == "" {
+		key = "ca.crt"
+	}
+	name := types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}
+
+	var caBundle []byte
+	var version string
+	if ref.Kind == "Secret" {
+		secret := &v1.Secret{}
+		if err := k8sClient.Get(ctx, name, secret); err != nil {
+			return nil, "", fmt.Errorf("failed to get CA bundle Secret %s: %w", name, err)
+		}
+		caBundle = secret.Data[key]
+		version = secret.ResourceVersion
+	} else {
+		configMap := &v1.ConfigMap{}
+		if err := k8sClient.Get(ctx, name, configMap); err != nil {
+			return nil, "", fmt.Errorf("failed to get CA bundle ConfigMap %s: %w", name, err)
+		}
+		caBundle = []byte(configMap.Data[key])
+		version = configMap.ResourceVersion
+	}
+	if len(caBundle) == 0 {
+		return nil, "", fmt.Errorf("CA bundle %s has no key %s", name, key)
+	}
+	return caBundle, version, nil
+}
@@ -0,0 +1,271 @@
+package k8s
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = Describe("VidraCluster clients", func() {
+	var (
+		ctx       = context.Background()
+		k8sClient client.Client
+		cluster   *infrahubv1alpha1.VidraCluster
+	)
+
+	BeforeEach(func() {
+		rawConfig := clientcmdapi.Config{
+			Clusters: map[string]*clientcmdapi.Cluster{
+				"lab":  {Server: "https://lab.example.com"},
+				"prod": {Server: "https://prod.example.com"},
+			},
+			AuthInfos: map[string]*clientcmdapi.AuthInfo{
+				"lab":  {Token: "lab-token"},
+				"prod": {Token: "prod-token"},
+			},
+			Contexts: map[string]*clientcmdapi.Context{
+				"lab":  {Cluster: "lab", AuthInfo: "lab"},
+				"prod": {Cluster: "prod", AuthInfo: "prod"},
+			},
+			CurrentContext: "lab",
+		}
+		configBytes, err := clientcmd.Write(rawConfig)
+		Expect(err).ToNot(HaveOccurred())
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(infrahubv1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "remote-kubeconfig", Namespace: "vidra-system"},
+				Data:       map[string][]byte{ClusterKubeconfigKey: configBytes},
+			},
+			&v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "remote-ca", Namespace: "vidra-system"},
+				Data:       map[string]string{"ca.crt": "remote-ca-bundle"},
+			},
+		).Build()
+
+		cluster = &infrahubv1alpha1.VidraCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "remote", Generation: 1},
+			Spec: infrahubv1alpha1.VidraClusterSpec{
+				Server:               "https://10.0.0.1:6443",
+				CredentialsSecretRef: &infrahubv1alpha1.ClusterCredentialsReference{Name: "remote-kubeconfig", Namespace: "vidra-system"},
+			},
+		}
+	})
+
+	It("should use the current context of the kubeconfig with the server of the cluster", func() {
+		restConfig, err := RESTConfigForCluster(ctx, k8sClient, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(restConfig.Host).To(Equal("https://10.0.0.1:6443"))
+		Expect(restConfig.BearerToken).To(Equal("lab-token"))
+	})
+
+	It("should use the context of the credentials reference", func() {
+		cluster.Spec.CredentialsSecretRef.Context = "prod"
+		restConfig, err := RESTConfigForCluster(ctx, k8sClient, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(restConfig.BearerToken).To(Equal("prod-token"))
+
+		cluster.Spec.CredentialsSecretRef.Context = "missing"
+		_, err = RESTConfigForCluster(ctx, k8sClient, cluster)
+		Expect(err).To(MatchError(ContainSubstring(`has no context "missing"`)))
+	})
+
+	It("should override the CA of the kubeconfig with the CA bundle of the cluster", func() {
+		cluster.Spec.CABundle = &infrahubv1alpha1.CABundleSource{Kind: "ConfigMap", Name: "remote-ca", Namespace: "vidra-system"}
+		restConfig, err := RESTConfigForCluster(ctx, k8sClient, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(restConfig.CAData)).To(Equal("remote-ca-bundle"))
+
+		cluster.Spec.CABundle.Key = "missing"
+		_, err = RESTConfigForCluster(ctx, k8sClient, cluster)
+		Expect(err).To(MatchError(ContainSubstring("has no key missing")))
+	})
+
+	It("should fail without a kubeconfig in the credentials Secret", func() {
+		cluster.Spec.CredentialsSecretRef.Name = "missing"
+		_, err := RESTConfigForCluster(ctx, k8sClient, cluster)
+		Expect(err).To(MatchError(ContainSubstring("failed to get credentials Secret vidra-system/missing")))
+
+		Expect(k8sClient.Create(ctx, &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "empty", Namespace: "vidra-system"}})).To(Succeed())
+		cluster.Spec.CredentialsSecretRef.Name = "empty"
+		_, err = RESTConfigForCluster(ctx, k8sClient, cluster)
+		Expect(err).To(MatchError(ContainSubstring("has no key kubeconfig")))
+	})
+
+	It("should reject kubeconfig users which need an exec plugin", func() {
+		rawConfig := clientcmdapi.Config{
+			Clusters:       map[string]*clientcmdapi.Cluster{"eks": {Server: "https://eks.example.com"}},
+			AuthInfos:      map[string]*clientcmdapi.AuthInfo{"eks": {Exec: &clientcmdapi.ExecConfig{Command: "aws"}}},
+			Contexts:       map[string]*clientcmdapi.Context{"eks": {Cluster: "eks", AuthInfo: "eks"}},
+			CurrentContext: "eks",
+		}
+		configBytes, err := clientcmd.Write(rawConfig)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(k8sClient.Create(ctx, &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "eks-kubeconfig", Namespace: "vidra-system"},
+			Data:       map[string][]byte{ClusterKubeconfigKey: configBytes},
+		})).To(Succeed())
+
+		cluster.Spec.CredentialsSecretRef.Name = "eks-kubeconfig"
+		_, err = RESTConfigForCluster(ctx, k8sClient, cluster)
+		Expect(err).To(MatchError(ContainSubstring("uses the exec plugin aws which cannot run in the operator")))
+	})
+
+	It("should authenticate with the token of the credentials Secret", func() {
+		Expect(k8sClient.Create(ctx, &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "remote-token", Namespace: "vidra-system"},
+			Data:       map[string][]byte{"token": []byte("remote-token\n"), "ca.crt": []byte("token-ca")},
+		})).To(Succeed())
+		cluster.Spec.CredentialsType = infrahubv1alpha1.ClusterCredentialsToken
+		cluster.Spec.CredentialsSecretRef.Name = "remote-token"
+
+		restConfig, err := RESTConfigForCluster(ctx, k8sClient, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(restConfig.Host).To(Equal("https://10.0.0.1:6443"))
+		Expect(restConfig.BearerToken).To(Equal("remote-token"))
+		Expect(string(restConfig.CAData)).To(Equal("token-ca"))
+
+		cluster.Spec.CredentialsSecretRef.Name = "remote-kubeconfig"
+		_, err = RESTConfigForCluster(ctx, k8sClient, cluster)
+		Expect(err).To(MatchError(ContainSubstring("has no key token")))
+	})
+
+	It("should authenticate with the client certificate of the credentials Secret", func() {
+		Expect(k8sClient.Create(ctx, &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "remote-tls", Namespace: "vidra-system"},
+			Data:       map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")},
+		})).To(Succeed())
+		cluster.Spec.CredentialsType = infrahubv1alpha1.ClusterCredentialsClientCertificate
+		cluster.Spec.CredentialsSecretRef.Name = "remote-tls"
+		cluster.Spec.InsecureSkipVerify = true
+
+		restConfig, err := RESTConfigForCluster(ctx, k8sClient, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(restConfig.CertData)).To(Equal("cert"))
+		Expect(string(restConfig.KeyData)).To(Equal("key"))
+		Expect(restConfig.Insecure).To(BeTrue())
+	})
+
+	It("should read a projected service account token from its file", func() {
+		cluster.Spec.CredentialsType = infrahubv1alpha1.ClusterCredentialsServiceAccountToken
+		cluster.Spec.CredentialsSecretRef = nil
+		cluster.Spec.TokenFile = "/var/run/secrets/vidra/remote/token"
+
+		restConfig, version, err := restConfigForCluster(ctx, k8sClient, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(restConfig.Host).To(Equal("https://10.0.0.1:6443"))
+		Expect(restConfig.BearerTokenFile).To(Equal("/var/run/secrets/vidra/remote/token"))
+		Expect(version).To(Equal("1"))
+
+		cluster.Spec.TokenFile = ""
+		_, err = RESTConfigForCluster(ctx, k8sClient, cluster)
+		Expect(err).To(MatchError(ContainSubstring("needs a tokenFile")))
+	})
+
+	It("should rebuild the client of a cluster after its credentials were rotated", func() {
+		factory := NewDynamicMulticlusterFactory()
+		first, err := factory.GetClientForCluster(ctx, cluster, k8sClient)
+		Expect(err).ToNot(HaveOccurred())
+
+		secret := &v1.Secret{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "remote-kubeconfig", Namespace: "vidra-system"}, secret)).To(Succeed())
+		secret.Labels = map[string]string{"rotated": "true"}
+		Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+
+		second, err := factory.GetClientForCluster(ctx, cluster, k8sClient)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(second).ToNot(BeIdenticalTo(first))
+
+		By("dropping the client of a deleted cluster")
+		factory.EvictCluster(cluster.Name)
+		third, err := factory.GetClientForCluster(ctx, cluster, k8sClient)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(third).ToNot(BeIdenticalTo(second))
+	})
+
+	It("should drop a client after repeated authentication errors", func() {
+		unauthorized := apierrors.NewUnauthorized("token expired")
+		failing := interceptor.NewClient(fake.NewClientBuilder().Build().(client.WithWatch), interceptor.Funcs{
+			Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+				return unauthorized
+			},
+		})
+		var evicted int
+		tracked := &authTrackingClient{Client: failing, cluster: "auth-test", evict: func() { evicted++ }}
+
+		for i := 0; i < maxAuthFailures-1; i++ {
+			Expect(tracked.Get(ctx, client.ObjectKey{Name: "x"}, &v1.ConfigMap{})).To(MatchError(unauthorized))
+		}
+		Expect(evicted).To(BeZero())
+		Expect(tracked.Get(ctx, client.ObjectKey{Name: "x"}, &v1.ConfigMap{})).To(MatchError(unauthorized))
+		Expect(evicted).To(Equal(1))
+		Expect(testutil.ToFloat64(clusterConnected.WithLabelValues("auth-test"))).To(BeZero())
+		Expect(testutil.ToFloat64(clusterAuthFailures.WithLabelValues("auth-test"))).To(BeNumerically("==", maxAuthFailures))
+
+		By("resetting the count once the API server answers")
+		tracked.Client = fake.NewClientBuilder().Build()
+		Expect(tracked.Get(ctx, client.ObjectKey{Name: "x"}, &v1.ConfigMap{})).To(MatchError(ContainSubstring("not found")))
+		Expect(tracked.failures.Load()).To(BeZero())
+		Expect(testutil.ToFloat64(clusterConnected.WithLabelValues("auth-test"))).To(Equal(1.0))
+	})
+
+	It("should label the metrics of a server client by its host and drop it after repeated authentication errors", func() {
+		serverURL := "https://legacy.example.com:6443"
+		factory := NewDynamicMulticlusterFactory()
+		c, err := factory.cachedClientFor("server/"+serverURL, serverClusterLabel(serverURL), "", &rest.Config{Host: serverURL})
+		Expect(err).ToNot(HaveOccurred())
+
+		tracked := c.(*authTrackingClient)
+		Expect(tracked.cluster).To(Equal("legacy:legacy.example.com"))
+		tracked.Client = interceptor.NewClient(fake.NewClientBuilder().Build().(client.WithWatch), interceptor.Funcs{
+			Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+				return apierrors.NewUnauthorized("token expired")
+			},
+		})
+		for i := 0; i < maxAuthFailures; i++ {
+			Expect(tracked.Get(ctx, client.ObjectKey{Name: "x"}, &v1.ConfigMap{})).ToNot(Succeed())
+		}
+
+		Expect(factory.clients).ToNot(HaveKey("server/" + serverURL))
+		Expect(testutil.ToFloat64(clusterConnected.WithLabelValues("legacy:legacy.example.com"))).To(BeZero())
+		Expect(testutil.ToFloat64(clusterClientEvictions.WithLabelValues("legacy:legacy.example.com"))).To(Equal(1.0))
+	})
+
+	It("should cache the client of a cluster until its spec changes", func() {
+		factory := NewDynamicMulticlusterFactory()
+		first, err := factory.GetClientForCluster(ctx, cluster, k8sClient)
+		Expect(err).ToNot(HaveOccurred())
+		second, err := factory.GetClientForCluster(ctx, cluster, k8sClient)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(second).To(BeIdenticalTo(first))
+
+		dynamicClient, err := factory.GetDynamicClientFor(first)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(dynamicClient).ToNot(BeNil())
+		_, err = factory.GetDynamicClientFor(k8sClient)
+		Expect(err).To(MatchError(ContainSubstring("not cached")))
+
+		cluster.Generation = 2
+		third, err := factory.GetClientForCluster(ctx, cluster, k8sClient)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(third).ToNot(BeIdenticalTo(first))
+	})
+})
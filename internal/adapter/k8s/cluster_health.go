@@ -0,0 +1,109 @@
+package k8s
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// maxAuthFailures is the number of consecutive authentication errors after which a cached client is dropped
+const maxAuthFailures = 3
+
+// legacyClusterPrefix prefixes the cluster label of servers of destinations, so they are told apart from VidraClusters
+const legacyClusterPrefix = "legacy:"
+
+var (
+	// clusterConnected reports per remote cluster whether its API server accepts the requests of the operator.
+	// The cluster label is the name of a VidraCluster or legacy:<host> for the server of a destination.
+	clusterConnected = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "vidra_cluster_connected",
+		Help: "Whether the API server of a remote cluster is reachable and accepts the credentials (1) or not (0)",
+	}, []string{"cluster"})
+	// clusterAuthFailures counts the requests to a remote cluster rejected as unauthorized
+	clusterAuthFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "vidra_cluster_auth_failures_total",
+		Help: "Requests to a remote cluster rejected as unauthorized",
+	}, []string{"cluster"})
+	// clusterClientEvictions counts the cached clients of a remote cluster dropped after authentication errors
+	clusterClientEvictions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "vidra_cluster_client_evictions_total",
+		Help: "Cached clients of a remote cluster dropped after repeated authentication errors",
+	}, []string{"cluster"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(clusterConnected, clusterAuthFailures, clusterClientEvictions)
+}
+
+// serverClusterLabel returns the cluster label of the metrics of the server of a destination
+func serverClusterLabel(serverURL string) string {
+	return legacyClusterPrefix + ServerHost(serverURL)
+}
+
+// setClusterConnected records the connection health of the cluster
+func setClusterConnected(cluster string, connected bool) {
+	value := 0.0
+	if connected {
+		value = 1
+	}
+	clusterConnected.WithLabelValues(cluster).Set(value)
+}
+
+// authTrackingClient counts consecutive authentication errors of a cached client and drops it from the factory
+// after maxAuthFailures, so the next reconcile builds a new client from the current credentials
+type authTrackingClient struct {
+	client.Client
+	cluster  string
+	failures atomic.Int32
+	evict    func()
+}
+
+// track records the result of a request
+func (c *authTrackingClient) track(err error) error {
+	switch {
+	case apierrors.IsUnauthorized(err):
+		clusterAuthFailures.WithLabelValues(c.cluster).Inc()
+		if c.failures.Add(1) == maxAuthFailures {
+			clusterClientEvictions.WithLabelValues(c.cluster).Inc()
+			setClusterConnected(c.cluster, false)
+			c.evict()
+		}
+	case err == nil, apierrors.ReasonForError(err) != "":
+		// Any other answer of the API server shows the credentials are accepted
+		c.failures.Store(0)
+		setClusterConnected(c.cluster, true)
+	}
+	return err
+}
+
+func (c *authTrackingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	return c.track(c.Client.Get(ctx, key, obj, opts...))
+}
+
+func (c *authTrackingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	return c.track(c.Client.List(ctx, list, opts...))
+}
+
+func (c *authTrackingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return c.track(c.Client.Create(ctx, obj, opts...))
+}
+
+func (c *authTrackingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	return c.track(c.Client.Delete(ctx, obj, opts...))
+}
+
+func (c *authTrackingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.track(c.Client.Update(ctx, obj, opts...))
+}
+
+func (c *authTrackingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return c.track(c.Client.Patch(ctx, obj, patch, opts...))
+}
+
+func (c *authTrackingClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	return c.track(c.Client.DeleteAllOf(ctx, obj, opts...))
+}
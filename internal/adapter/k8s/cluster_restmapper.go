@@ -0,0 +1,84 @@
+package k8s
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+// minMapperRefreshInterval bounds how often a mapping miss triggers a new discovery of a remote cluster,
+// so a manifest with an unknown kind does not query the API server for every object
+const minMapperRefreshInterval = 10 * time.Second
+
+// clusterRESTMapper maps kinds to resources with the discovery information of a remote cluster. The discovery
+// runs on the first mapping and again after a mapping miss, e.g. once a CRD was installed on the cluster.
+type clusterRESTMapper struct {
+	*restmapper.DeferredDiscoveryRESTMapper
+
+	mu          sync.Mutex
+	lastRefresh time.Time
+	now         func() time.Time
+}
+
+// newClusterRESTMapper returns a lazy RESTMapper backed by the discovery of the remote cluster
+func newClusterRESTMapper(restConfig *rest.Config) (*clusterRESTMapper, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	return newClusterRESTMapperFor(discoveryClient), nil
+}
+
+// newClusterRESTMapperFor returns a lazy RESTMapper backed by the discovery client
+func newClusterRESTMapperFor(discoveryClient discovery.DiscoveryInterface) *clusterRESTMapper {
+	return &clusterRESTMapper{
+		DeferredDiscoveryRESTMapper: restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
+		now:                         time.Now,
+	}
+}
+
+// refresh drops the discovery information after a mapping miss and reports whether it was dropped
+func (m *clusterRESTMapper) refresh(err error) bool {
+	if !meta.IsNoMatchError(err) {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if now := m.now(); now.Sub(m.lastRefresh) >= minMapperRefreshInterval {
+		m.lastRefresh = now
+		m.Reset()
+		return true
+	}
+	return false
+}
+
+func (m *clusterRESTMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	mapping, err := m.DeferredDiscoveryRESTMapper.RESTMapping(gk, versions...)
+	if m.refresh(err) {
+		return m.DeferredDiscoveryRESTMapper.RESTMapping(gk, versions...)
+	}
+	return mapping, err
+}
+
+func (m *clusterRESTMapper) RESTMappings(gk schema.GroupKind, versions ...string) ([]*meta.RESTMapping, error) {
+	mappings, err := m.DeferredDiscoveryRESTMapper.RESTMappings(gk, versions...)
+	if m.refresh(err) {
+		return m.DeferredDiscoveryRESTMapper.RESTMappings(gk, versions...)
+	}
+	return mappings, err
+}
+
+func (m *clusterRESTMapper) KindFor(resource schema.GroupVersionResource) (schema.GroupVersionKind, error) {
+	gvk, err := m.DeferredDiscoveryRESTMapper.KindFor(resource)
+	if m.refresh(err) {
+		return m.DeferredDiscoveryRESTMapper.KindFor(resource)
+	}
+	return gvk, err
+}
@@ -0,0 +1,61 @@
+package k8s
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("VidraCluster REST mapper", func() {
+	var (
+		discovery *fakediscovery.FakeDiscovery
+		mapper    *clusterRESTMapper
+		now       time.Time
+		widget    = schema.GroupKind{Group: "lab.example.com", Kind: "Widget"}
+	)
+
+	BeforeEach(func() {
+		discovery = &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+		discovery.Resources = []*metav1.APIResourceList{{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{{Name: "configmaps", Kind: "ConfigMap", Namespaced: true}},
+		}}
+		now = time.Now()
+		mapper = newClusterRESTMapperFor(discovery)
+		mapper.now = func() time.Time { return now }
+	})
+
+	It("should map kinds with the discovery of the cluster", func() {
+		mapping, err := mapper.RESTMapping(schema.GroupKind{Kind: "ConfigMap"}, "v1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mapping.Resource.Resource).To(Equal("configmaps"))
+		Expect(mapping.Scope.Name()).To(Equal(meta.RESTScopeNameNamespace))
+	})
+
+	It("should discover a CRD installed after the first mapping", func() {
+		_, err := mapper.RESTMapping(widget, "v1")
+		Expect(meta.IsNoMatchError(err)).To(BeTrue())
+
+		discovery.Resources = append(discovery.Resources, &metav1.APIResourceList{
+			GroupVersion: "lab.example.com/v1",
+			APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget"}},
+		})
+
+		By("not refreshing again right after a miss")
+		_, err = mapper.RESTMapping(widget, "v1")
+		Expect(meta.IsNoMatchError(err)).To(BeTrue())
+
+		now = now.Add(minMapperRefreshInterval)
+		mapping, err := mapper.RESTMapping(widget, "v1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mapping.Resource.Resource).To(Equal("widgets"))
+		Expect(mapping.Scope.Name()).To(Equal(meta.RESTScopeNameRoot))
+	})
+})
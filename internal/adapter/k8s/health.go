@@ -0,0 +1,241 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+type healthCheckFunc func(obj *unstructured.Unstructured) (infrahubv1alpha1.HealthStatus, string)
+
+// HealthChecker assesses the health of live resources with built-in checks for common kinds.
+// Custom CEL rules per GroupKind (e.g. "Certificate.cert-manager.io") take precedence over the built-in checks.
+type HealthChecker struct {
+	builtin map[string]healthCheckFunc
+	rules   map[string]cel.Program
+}
+
+func NewHealthChecker() *HealthChecker {
+	return &HealthChecker{
+		builtin: map[string]healthCheckFunc{
+			"Deployment.apps":       deploymentHealth,
+			"StatefulSet.apps":      statefulSetHealth,
+			"DaemonSet.apps":        daemonSetHealth,
+			"Job.batch":             jobHealth,
+			"PersistentVolumeClaim": pvcHealth,
+			"Service":               serviceHealth,
+		},
+		rules: map[string]cel.Program{},
+	}
+}
+
+// NewHealthCheckerWithRules parses the custom health rules (a YAML map of GroupKind to CEL expression)
+// and compiles them. The expression gets the live resource as `object` and must return either
+// a health status string (Healthy, Progressing, Degraded) or a bool (true = Healthy, false = Progressing).
+func NewHealthCheckerWithRules(rawRules string) (*HealthChecker, error) {
+	checker := NewHealthChecker()
+	if strings.TrimSpace(rawRules) == "" {
+		return checker, nil
+	}
+
+	rules := map[string]string{}
+	if err := yaml.Unmarshal([]byte(rawRules), &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse health rules: %w", err)
+	}
+
+	env, err := cel.NewEnv(cel.Variable("object", cel.DynType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+	for groupKind, expression := range rules {
+		ast, issues := env.Compile(expression)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("failed to compile health rule for %s: %w", groupKind, issues.Err())
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build health rule for %s: %w", groupKind, err)
+		}
+		checker.rules[groupKind] = program
+	}
+	return checker, nil
+}
+
+// Check returns the health of the live resource and a message if it is not healthy.
+// Resources without a check are healthy as soon as they exist.
+func (h *HealthChecker) Check(obj *unstructured.Unstructured) (infrahubv1alpha1.HealthStatus, string) {
+	groupKind := obj.GroupVersionKind().GroupKind().String()
+	if program, ok := h.rules[groupKind]; ok {
+		return evaluateHealthRule(program, obj)
+	}
+	if check, ok := h.builtin[groupKind]; ok {
+		return check(obj)
+	}
+	return infrahubv1alpha1.HealthHealthy, ""
+}
+
+func evaluateHealthRule(program cel.Program, obj *unstructured.Unstructured) (infrahubv1alpha1.HealthStatus, string) {
+	out, _, err := program.Eval(map[string]interface{}{"object": obj.Object})
+	if err != nil {
+		return infrahubv1alpha1.HealthUnknown, fmt.Sprintf("health rule failed: %v", err)
+	}
+	switch value := out.Value().(type) {
+	case bool:
+		if value {
+			return infrahubv1alpha1.HealthHealthy, ""
+		}
+		return infrahubv1alpha1.HealthProgressing, "health rule is not fulfilled"
+	case string:
+		switch status := infrahubv1alpha1.HealthStatus(value); status {
+		case infrahubv1alpha1.HealthHealthy, infrahubv1alpha1.HealthProgressing, infrahubv1alpha1.HealthDegraded, infrahubv1alpha1.HealthUnknown:
+			return status, ""
+		}
+	}
+	return infrahubv1alpha1.HealthUnknown, fmt.Sprintf("health rule returned unsupported value: %v", out.Value())
+}
+
+// AggregateHealth returns the worst of the given health states, an empty list is healthy.
+func AggregateHealth(states ...infrahubv1alpha1.HealthStatus) infrahubv1alpha1.HealthStatus {
+	rank := map[infrahubv1alpha1.HealthStatus]int{
+		infrahubv1alpha1.HealthHealthy:     0,
+		infrahubv1alpha1.HealthProgressing: 1,
+		infrahubv1alpha1.HealthUnknown:     2,
+		infrahubv1alpha1.HealthDegraded:    3,
+	}
+	worst := infrahubv1alpha1.HealthHealthy
+	for _, state := range states {
+		if state == "" {
+			state = infrahubv1alpha1.HealthUnknown
+		}
+		if rank[state] > rank[worst] {
+			worst = state
+		}
+	}
+	return worst
+}
+
+// Built-in checks
+
+func deploymentHealth(obj *unstructured.Unstructured) (infrahubv1alpha1.HealthStatus, string) {
+	if status, msg, done := generationObserved(obj); !done {
+		return status, msg
+	}
+	for _, cond := range conditions(obj) {
+		if cond["type"] == "Progressing" && cond["reason"] == "ProgressDeadlineExceeded" {
+			return infrahubv1alpha1.HealthDegraded, fmt.Sprintf("deployment %s exceeded its progress deadline", obj.GetName())
+		}
+	}
+	desired := int64Field(obj, 1, "spec", "replicas")
+	updated := int64Field(obj, 0, "status", "updatedReplicas")
+	replicas := int64Field(obj, 0, "status", "replicas")
+	available := int64Field(obj, 0, "status", "availableReplicas")
+	switch {
+	case updated < desired:
+		return infrahubv1alpha1.HealthProgressing, fmt.Sprintf("%d of %d replicas updated", updated, desired)
+	case replicas > updated:
+		return infrahubv1alpha1.HealthProgressing, fmt.Sprintf("%d old replicas pending termination", replicas-updated)
+	case available < updated:
+		return infrahubv1alpha1.HealthProgressing, fmt.Sprintf("%d of %d updated replicas available", available, updated)
+	}
+	return infrahubv1alpha1.HealthHealthy, ""
+}
+
+func statefulSetHealth(obj *unstructured.Unstructured) (infrahubv1alpha1.HealthStatus, string) {
+	if status, msg, done := generationObserved(obj); !done {
+		return status, msg
+	}
+	desired := int64Field(obj, 1, "spec", "replicas")
+	ready := int64Field(obj, 0, "status", "readyReplicas")
+	if ready < desired {
+		return infrahubv1alpha1.HealthProgressing, fmt.Sprintf("%d of %d replicas ready", ready, desired)
+	}
+	currentRevision, _, _ := unstructured.NestedString(obj.Object, "status", "currentRevision")
+	updateRevision, _, _ := unstructured.NestedString(obj.Object, "status", "updateRevision")
+	if updateRevision != "" && currentRevision != updateRevision {
+		return infrahubv1alpha1.HealthProgressing, fmt.Sprintf("rolling out revision %s", updateRevision)
+	}
+	return infrahubv1alpha1.HealthHealthy, ""
+}
+
+func daemonSetHealth(obj *unstructured.Unstructured) (infrahubv1alpha1.HealthStatus, string) {
+	if status, msg, done := generationObserved(obj); !done {
+		return status, msg
+	}
+	desired := int64Field(obj, 0, "status", "desiredNumberScheduled")
+	updated := int64Field(obj, 0, "status", "updatedNumberScheduled")
+	available := int64Field(obj, 0, "status", "numberAvailable")
+	if updated < desired || available < desired {
+		return infrahubv1alpha1.HealthProgressing, fmt.Sprintf("%d of %d pods updated and available", min(updated, available), desired)
+	}
+	return infrahubv1alpha1.HealthHealthy, ""
+}
+
+func jobHealth(obj *unstructured.Unstructured) (infrahubv1alpha1.HealthStatus, string) {
+	for _, cond := range conditions(obj) {
+		if cond["status"] != "True" {
+			continue
+		}
+		switch cond["type"] {
+		case "Failed":
+			return infrahubv1alpha1.HealthDegraded, fmt.Sprintf("job failed: %v", cond["message"])
+		case "Complete":
+			return infrahubv1alpha1.HealthHealthy, ""
+		}
+	}
+	return infrahubv1alpha1.HealthProgressing, "job is running"
+}
+
+func pvcHealth(obj *unstructured.Unstructured) (infrahubv1alpha1.HealthStatus, string) {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	switch phase {
+	case "Bound":
+		return infrahubv1alpha1.HealthHealthy, ""
+	case "Lost":
+		return infrahubv1alpha1.HealthDegraded, "persistent volume claim lost its volume"
+	}
+	return infrahubv1alpha1.HealthProgressing, "persistent volume claim is not bound yet"
+}
+
+func serviceHealth(obj *unstructured.Unstructured) (infrahubv1alpha1.HealthStatus, string) {
+	serviceType, _, _ := unstructured.NestedString(obj.Object, "spec", "type")
+	if serviceType != "LoadBalancer" {
+		return infrahubv1alpha1.HealthHealthy, ""
+	}
+	ingress, _, _ := unstructured.NestedSlice(obj.Object, "status", "loadBalancer", "ingress")
+	if len(ingress) == 0 {
+		return infrahubv1alpha1.HealthProgressing, "waiting for load balancer ingress"
+	}
+	return infrahubv1alpha1.HealthHealthy, ""
+}
+
+// generationObserved reports whether the controller of the resource has seen its latest generation.
+func generationObserved(obj *unstructured.Unstructured) (infrahubv1alpha1.HealthStatus, string, bool) {
+	observed, found, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if !found || observed < obj.GetGeneration() {
+		return infrahubv1alpha1.HealthProgressing, "waiting for the controller to observe the latest generation", false
+	}
+	return "", "", true
+}
+
+func conditions(obj *unstructured.Unstructured) []map[string]interface{} {
+	raw, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	conds := make([]map[string]interface{}, 0, len(raw))
+	for _, c := range raw {
+		if cond, ok := c.(map[string]interface{}); ok {
+			conds = append(conds, cond)
+		}
+	}
+	return conds
+}
+
+func int64Field(obj *unstructured.Unstructured, defaultValue int64, fields ...string) int64 {
+	value, found, err := unstructured.NestedInt64(obj.Object, fields...)
+	if !found || err != nil {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,174 @@
+package k8s
+
+import (
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newLiveObject(apiVersion, kind string, spec, status map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":       "example",
+			"generation": int64(2),
+		},
+	}}
+	if spec != nil {
+		obj.Object["spec"] = spec
+	}
+	if status != nil {
+		obj.Object["status"] = status
+	}
+	return obj
+}
+
+var _ = Describe("HealthChecker", func() {
+	var checker *HealthChecker
+
+	BeforeEach(func() {
+		checker = NewHealthChecker()
+	})
+
+	It("should report resources without a check as healthy", func() {
+		health, _ := checker.Check(newLiveObject("v1", "ConfigMap", nil, nil))
+		Expect(health).To(Equal(infrahubv1alpha1.HealthHealthy))
+	})
+
+	It("should report a Deployment as progressing until the rollout is complete", func() {
+		deployment := newLiveObject("apps/v1", "Deployment",
+			map[string]interface{}{"replicas": int64(3)},
+			map[string]interface{}{"observedGeneration": int64(2), "replicas": int64(3), "updatedReplicas": int64(3), "availableReplicas": int64(1)})
+		health, message := checker.Check(deployment)
+		Expect(health).To(Equal(infrahubv1alpha1.HealthProgressing))
+		Expect(message).To(Equal("1 of 3 updated replicas available"))
+
+		Expect(unstructured.SetNestedField(deployment.Object, int64(3), "status", "availableReplicas")).To(Succeed())
+		health, _ = checker.Check(deployment)
+		Expect(health).To(Equal(infrahubv1alpha1.HealthHealthy))
+	})
+
+	It("should report a Deployment as progressing if the latest generation was not observed", func() {
+		deployment := newLiveObject("apps/v1", "Deployment", nil, map[string]interface{}{"observedGeneration": int64(1)})
+		health, _ := checker.Check(deployment)
+		Expect(health).To(Equal(infrahubv1alpha1.HealthProgressing))
+	})
+
+	It("should report a Deployment as degraded if the progress deadline is exceeded", func() {
+		deployment := newLiveObject("apps/v1", "Deployment", nil, map[string]interface{}{
+			"observedGeneration": int64(2),
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded"},
+			},
+		})
+		health, _ := checker.Check(deployment)
+		Expect(health).To(Equal(infrahubv1alpha1.HealthDegraded))
+	})
+
+	It("should report the state of a StatefulSet by its ready replicas", func() {
+		statefulSet := newLiveObject("apps/v1", "StatefulSet",
+			map[string]interface{}{"replicas": int64(2)},
+			map[string]interface{}{"observedGeneration": int64(2), "readyReplicas": int64(1)})
+		health, _ := checker.Check(statefulSet)
+		Expect(health).To(Equal(infrahubv1alpha1.HealthProgressing))
+
+		Expect(unstructured.SetNestedField(statefulSet.Object, int64(2), "status", "readyReplicas")).To(Succeed())
+		health, _ = checker.Check(statefulSet)
+		Expect(health).To(Equal(infrahubv1alpha1.HealthHealthy))
+	})
+
+	It("should report the state of a Job by its conditions", func() {
+		job := newLiveObject("batch/v1", "Job", nil, nil)
+		health, _ := checker.Check(job)
+		Expect(health).To(Equal(infrahubv1alpha1.HealthProgressing))
+
+		job.Object["status"] = map[string]interface{}{"conditions": []interface{}{
+			map[string]interface{}{"type": "Failed", "status": "True", "message": "BackoffLimitExceeded"},
+		}}
+		health, message := checker.Check(job)
+		Expect(health).To(Equal(infrahubv1alpha1.HealthDegraded))
+		Expect(message).To(ContainSubstring("BackoffLimitExceeded"))
+
+		job.Object["status"] = map[string]interface{}{"conditions": []interface{}{
+			map[string]interface{}{"type": "Complete", "status": "True"},
+		}}
+		health, _ = checker.Check(job)
+		Expect(health).To(Equal(infrahubv1alpha1.HealthHealthy))
+	})
+
+	It("should report the state of a PersistentVolumeClaim by its phase", func() {
+		health, _ := checker.Check(newLiveObject("v1", "PersistentVolumeClaim", nil, map[string]interface{}{"phase": "Pending"}))
+		Expect(health).To(Equal(infrahubv1alpha1.HealthProgressing))
+		health, _ = checker.Check(newLiveObject("v1", "PersistentVolumeClaim", nil, map[string]interface{}{"phase": "Bound"}))
+		Expect(health).To(Equal(infrahubv1alpha1.HealthHealthy))
+	})
+
+	It("should wait for the ingress of a LoadBalancer Service", func() {
+		service := newLiveObject("v1", "Service", map[string]interface{}{"type": "LoadBalancer"}, nil)
+		health, _ := checker.Check(service)
+		Expect(health).To(Equal(infrahubv1alpha1.HealthProgressing))
+
+		service.Object["status"] = map[string]interface{}{"loadBalancer": map[string]interface{}{
+			"ingress": []interface{}{map[string]interface{}{"ip": "10.0.0.1"}},
+		}}
+		health, _ = checker.Check(service)
+		Expect(health).To(Equal(infrahubv1alpha1.HealthHealthy))
+
+		health, _ = checker.Check(newLiveObject("v1", "Service", map[string]interface{}{"type": "ClusterIP"}, nil))
+		Expect(health).To(Equal(infrahubv1alpha1.HealthHealthy))
+	})
+
+	It("should prefer custom CEL rules over the built-in checks", func() {
+		checker, err := NewHealthCheckerWithRules(`
+Certificate.cert-manager.io: "object.status.conditions.exists(c, c.type == 'Ready' && c.status == 'True')"
+Deployment.apps: "'Degraded'"
+`)
+		Expect(err).NotTo(HaveOccurred())
+
+		certificate := newLiveObject("cert-manager.io/v1", "Certificate", nil, map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "False"}},
+		})
+		health, _ := checker.Check(certificate)
+		Expect(health).To(Equal(infrahubv1alpha1.HealthProgressing))
+
+		certificate.Object["status"] = map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}},
+		}
+		health, _ = checker.Check(certificate)
+		Expect(health).To(Equal(infrahubv1alpha1.HealthHealthy))
+
+		health, _ = checker.Check(newLiveObject("apps/v1", "Deployment", nil, nil))
+		Expect(health).To(Equal(infrahubv1alpha1.HealthDegraded))
+	})
+
+	It("should report unknown health if a custom rule fails or returns an unsupported value", func() {
+		checker, err := NewHealthCheckerWithRules(`
+ConfigMap: "object.data.missing == 'x'"
+Secret: "42"
+`)
+		Expect(err).NotTo(HaveOccurred())
+
+		health, _ := checker.Check(newLiveObject("v1", "ConfigMap", nil, nil))
+		Expect(health).To(Equal(infrahubv1alpha1.HealthUnknown))
+		health, message := checker.Check(newLiveObject("v1", "Secret", nil, nil))
+		Expect(health).To(Equal(infrahubv1alpha1.HealthUnknown))
+		Expect(message).To(ContainSubstring("unsupported value"))
+	})
+
+	It("should reject health rules which do not compile", func() {
+		_, err := NewHealthCheckerWithRules(`Deployment.apps: "object.status.("`)
+		Expect(err).To(MatchError(ContainSubstring("failed to compile health rule for Deployment.apps")))
+	})
+})
+
+var _ = Describe("AggregateHealth", func() {
+	It("should return the worst health", func() {
+		Expect(AggregateHealth()).To(Equal(infrahubv1alpha1.HealthHealthy))
+		Expect(AggregateHealth(infrahubv1alpha1.HealthHealthy, infrahubv1alpha1.HealthProgressing)).To(Equal(infrahubv1alpha1.HealthProgressing))
+		Expect(AggregateHealth(infrahubv1alpha1.HealthDegraded, infrahubv1alpha1.HealthUnknown)).To(Equal(infrahubv1alpha1.HealthDegraded))
+		Expect(AggregateHealth(infrahubv1alpha1.HealthHealthy, "")).To(Equal(infrahubv1alpha1.HealthUnknown))
+	})
+})
@@ -5,35 +5,101 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// ClusterKubeconfigLabel labels the kubeconfig Secrets of the servers of destinations with the host of the server
+const ClusterKubeconfigLabel = "cluster-kubeconfig"
+
+// clusterProbeTimeout bounds the request reading the version of a VidraCluster
+const clusterProbeTimeout = 10 * time.Second
+
 type DynamicMulticlusterFactory struct {
-	mu      sync.Mutex
-	clients map[string]client.Client
+	mu sync.Mutex
+	// clients holds the clients by "server/<url>" for the server of a destination and "cluster/<name>" for a VidraCluster
+	clients map[string]cachedClient
+}
+
+// cachedClient is a client built from a version of the credentials, it is rebuilt once the credentials change
+type cachedClient struct {
+	version string
+	client  *authTrackingClient
+	// dynamic is the dynamic client of the cluster for the watchers of event-based reconciliation
+	dynamic dynamic.Interface
 }
 
 func NewDynamicMulticlusterFactory() *DynamicMulticlusterFactory {
 	return &DynamicMulticlusterFactory{
-		clients: make(map[string]client.Client),
+		clients: make(map[string]cachedClient),
+	}
+}
+
+// GetClientForCluster returns the cached client of the VidraCluster. It is rebuilt once the spec of the cluster,
+// its credentials Secret or its CA bundle changed.
+func (f *DynamicMulticlusterFactory) GetClientForCluster(ctx context.Context, cluster *infrahubv1alpha1.VidraCluster, k8sClient client.Client) (client.Client, error) {
+	restConfig, version, err := restConfigForCluster(ctx, k8sClient, cluster)
+	if err != nil {
+		return nil, err
 	}
+	return f.cachedClientFor("cluster/"+cluster.Name, cluster.Name, version, restConfig)
 }
 
+// ServerVersion connects to the API server of the VidraCluster and returns its Kubernetes version
+func (f *DynamicMulticlusterFactory) ServerVersion(ctx context.Context, cluster *infrahubv1alpha1.VidraCluster, k8sClient client.Client) (string, error) {
+	restConfig, err := RESTConfigForCluster(ctx, k8sClient, cluster)
+	if err != nil {
+		setClusterConnected(cluster.Name, false)
+		return "", err
+	}
+	restConfig.Timeout = clusterProbeTimeout
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		setClusterConnected(cluster.Name, false)
+		return "", fmt.Errorf("failed to create discovery client for cluster %s: %w", cluster.Name, err)
+	}
+	info, err := discoveryClient.ServerVersion()
+	if err != nil {
+		setClusterConnected(cluster.Name, false)
+		if apierrors.IsUnauthorized(err) {
+			// Credentials which are rejected by the probe are rejected by the cached client as well
+			f.evict("cluster/"+cluster.Name, nil)
+		}
+		return "", fmt.Errorf("failed to get the version of cluster %s: %w", cluster.Name, err)
+	}
+	setClusterConnected(cluster.Name, true)
+	return info.GitVersion, nil
+}
+
+// EvictCluster drops the cached client of the VidraCluster and its connection metric, e.g. after it was deleted
+func (f *DynamicMulticlusterFactory) EvictCluster(name string) {
+	f.evict("cluster/"+name, nil)
+	clusterConnected.DeleteLabelValues(name)
+}
+
+// GetCachedClientFor returns the cached client of the server, built from the kubeconfig Secret labeled with the
+// host of the server. The client is kept until EvictServer is called for a change of such a Secret.
 func (f *DynamicMulticlusterFactory) GetCachedClientFor(ctx context.Context, serverURL string, k8sClient client.Client) (client.Client, error) {
+	key := "server/" + serverURL
 	f.mu.Lock()
-	defer f.mu.Unlock()
-
-	if cached, ok := f.clients[serverURL]; ok {
-		return cached, nil
+	cached, ok := f.clients[key]
+	f.mu.Unlock()
+	if ok {
+		return cached.client, nil
 	}
 
 	secretList := &v1.SecretList{}
-
-	trimmedK8SURL := strings.TrimPrefix(strings.Split(serverURL, ":")[1], "//")
-	err := GetSortedListByLabel(ctx, k8sClient, "cluster-kubeconfig", trimmedK8SURL, secretList)
+	trimmedK8SURL := ServerHost(serverURL)
+	err := GetSortedListByLabel(ctx, k8sClient, ClusterKubeconfigLabel, trimmedK8SURL, secretList)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get secrets by label: %w", err)
 	}
@@ -78,11 +144,82 @@ func (f *DynamicMulticlusterFactory) GetCachedClientFor(ctx context.Context, ser
 		return nil, fmt.Errorf("failed to create REST config from kubeconfig: %w", err)
 	}
 
-	cachedClient, err := client.New(restConfig, client.Options{})
+	return f.cachedClientFor(key, serverClusterLabel(serverURL), "", restConfig)
+}
+
+// EvictServer drops the cached clients of the servers with the host, e.g. after their kubeconfig Secret changed
+func (f *DynamicMulticlusterFactory) EvictServer(host string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for key := range f.clients {
+		if serverURL, ok := strings.CutPrefix(key, "server/"); ok && ServerHost(serverURL) == host {
+			delete(f.clients, key)
+		}
+	}
+}
+
+// ServerHost returns the host of the server URL, which kubeconfig Secrets are labeled with
+func ServerHost(serverURL string) string {
+	parts := strings.Split(serverURL, ":")
+	if len(parts) < 2 {
+		return serverURL
+	}
+	return strings.TrimPrefix(parts[1], "//")
+}
+
+// cachedClientFor returns the cached client of the key if it was built from the same version of the credentials,
+// otherwise it builds and caches a new client. The cluster is the label of the metrics of the client.
+func (f *DynamicMulticlusterFactory) cachedClientFor(key, cluster, version string, restConfig *rest.Config) (client.Client, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if cached, ok := f.clients[key]; ok && cached.version == version {
+		return cached.client, nil
+	}
+
+	// The client maps kinds with the discovery of the remote cluster, so CRDs which only exist there are known.
+	// The reconciler reads the mapper back through RESTMapper() of the client.
+	mapper, err := newClusterRESTMapper(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST mapper: %w", err)
+	}
+	newClient, err := client.New(restConfig, client.Options{Mapper: mapper})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create cached client: %w", err)
 	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	tracked := &authTrackingClient{Client: newClient, cluster: cluster}
+	tracked.evict = func() { f.evict(key, tracked) }
+
+	f.clients[key] = cachedClient{version: version, client: tracked, dynamic: dynamicClient}
+	return tracked, nil
+}
+
+// GetDynamicClientFor returns the dynamic client of the cluster of a client returned by the factory. It is
+// rebuilt together with the client.
+func (f *DynamicMulticlusterFactory) GetDynamicClientFor(c client.Client) (dynamic.Interface, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, cached := range f.clients {
+		if client.Client(cached.client) == c {
+			return cached.dynamic, nil
+		}
+	}
+	return nil, fmt.Errorf("client is not cached by the factory")
+}
 
-	f.clients[serverURL] = cachedClient
-	return cachedClient, nil
+// evict drops the cached client of the key. If only is set, the client is only dropped if it is still cached,
+// so a client which was already rebuilt is kept.
+func (f *DynamicMulticlusterFactory) evict(key string, only *authTrackingClient) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if cached, ok := f.clients[key]; ok && (only == nil || cached.client == only) {
+		delete(f.clients, key)
+	}
 }
@@ -94,6 +94,26 @@ var _ = Describe("DynamicMulticlusterFactory", func() {
 		Expect(c2).To(Equal(c1)) // should be the same cached client
 	})
 
+	It("should rebuild the client once the server was evicted", func() {
+		c1, err := factory.GetCachedClientFor(ctx, serverURL, k8sClient)
+		Expect(err).ToNot(HaveOccurred())
+
+		secret := &v1.Secret{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "cluster-kubeconfig", Namespace: namespace}, secret)).To(Succeed())
+		secret.Annotations = map[string]string{"rotated": "true"}
+		Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+
+		c2, err := factory.GetCachedClientFor(ctx, serverURL, k8sClient)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c2).To(BeIdenticalTo(c1))
+
+		factory.EvictServer("my-cluster.example.com")
+
+		c3, err := factory.GetCachedClientFor(ctx, serverURL, k8sClient)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(c3).ToNot(BeIdenticalTo(c1))
+	})
+
 	It("should fail if GetSortedListByLabel fails", func() {
 		By("Deleting the secret to simulate failure")
 		err := k8sClient.Delete(ctx, &v1.Secret{
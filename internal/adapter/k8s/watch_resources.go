@@ -22,13 +22,82 @@ type DynamicWatcherFactory struct {
 	mu       sync.Mutex
 	started  map[schema.GroupVersionResource]struct{}
 	stopChan chan struct{}
+	stopped  bool
+	// clusters holds a watcher factory per remote cluster
+	clusters map[string]*clusterWatcher
+}
+
+// clusterWatcher watches the resources of a remote cluster with the dynamic client it was started with
+type clusterWatcher struct {
+	dynamicClient dynamic.Interface
+	factory       *DynamicWatcherFactory
 }
 
 func NewDynamicWatcherFactory() *DynamicWatcherFactory {
 	return &DynamicWatcherFactory{
 		started:  make(map[schema.GroupVersionResource]struct{}),
 		stopChan: make(chan struct{}),
+		clusters: make(map[string]*clusterWatcher),
+	}
+}
+
+// StartWatchingClusterGVRs watches the GVRs on the remote cluster with a watcher factory of its own. If the
+// dynamic client of the cluster was rebuilt, e.g. after a credential rotation, the watchers are restarted with it.
+func (f *DynamicWatcherFactory) StartWatchingClusterGVRs(
+	cluster string,
+	dynamicClient dynamic.Interface,
+	gvrs []schema.GroupVersionResource,
+	onEvent domain.ResourceCallback,
+) {
+	f.mu.Lock()
+	watcher, ok := f.clusters[cluster]
+	if ok && watcher.dynamicClient != dynamicClient {
+		log.Printf("[WATCH] Restarting watchers of cluster %s with a new client", cluster)
+		gvrs = append(watcher.factory.watchedGVRs(), gvrs...)
+		watcher.factory.Stop()
+		ok = false
+	}
+	if !ok {
+		watcher = &clusterWatcher{dynamicClient: dynamicClient, factory: NewDynamicWatcherFactory()}
+		f.clusters[cluster] = watcher
+	}
+	f.mu.Unlock()
+
+	watcher.factory.StartWatchingGVRs(dynamicClient, gvrs, onEvent)
+}
+
+// StopWatchingCluster stops all watchers of the remote cluster
+func (f *DynamicWatcherFactory) StopWatchingCluster(cluster string) {
+	f.mu.Lock()
+	watcher, ok := f.clusters[cluster]
+	delete(f.clusters, cluster)
+	f.mu.Unlock()
+
+	if ok {
+		watcher.factory.Stop()
+		log.Printf("[WATCH] Stopped watching cluster %s", cluster)
+	}
+}
+
+// Stop stops all watchers started by the factory
+func (f *DynamicWatcherFactory) Stop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.stopped {
+		f.stopped = true
+		close(f.stopChan)
+	}
+}
+
+// watchedGVRs returns the GVRs the factory watches
+func (f *DynamicWatcherFactory) watchedGVRs() []schema.GroupVersionResource {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	gvrs := make([]schema.GroupVersionResource, 0, len(f.started))
+	for gvr := range f.started {
+		gvrs = append(gvrs, gvr)
 	}
+	return gvrs
 }
 
 func (f *DynamicWatcherFactory) StartWatchingGVRs(
@@ -38,9 +107,9 @@ func (f *DynamicWatcherFactory) StartWatchingGVRs(
 ) {
 	for _, gvr := range gvrs {
 		f.mu.Lock()
-		if _, ok := f.started[gvr]; ok {
+		if _, ok := f.started[gvr]; ok || f.stopped {
 			f.mu.Unlock()
-			continue // already watching
+			continue // already watching or stopped
 		}
 		f.started[gvr] = struct{}{}
 		f.mu.Unlock()
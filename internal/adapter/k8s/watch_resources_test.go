@@ -86,6 +86,41 @@ var _ = Describe("DynamicWatcher", func() {
 		})
 	})
 
+	Describe("StartWatchingClusterGVRs", func() {
+		It("should watch each remote cluster with a factory of its own until it is stopped", func() {
+			listKinds := map[schema.GroupVersionResource]string{gvr: "FooList"}
+			otherGVR := schema.GroupVersionResource{Group: "test", Version: "v1", Resource: "bars"}
+			listKinds[otherGVR] = "BarList"
+			first := fake.NewSimpleDynamicClientWithCustomListKinds(&scheme, listKinds)
+			cb := new(callbackMock)
+
+			factory := NewDynamicWatcherFactory()
+			factory.StartWatchingClusterGVRs("cluster/lab", first, []schema.GroupVersionResource{gvr}, cb.Callback)
+			factory.StartWatchingClusterGVRs("cluster/prod", first, []schema.GroupVersionResource{gvr}, cb.Callback)
+			Expect(factory.clusters).To(HaveLen(2))
+			Expect(factory.started).To(BeEmpty())
+			lab := factory.clusters["cluster/lab"].factory
+			Expect(lab.watchedGVRs()).To(ConsistOf(gvr))
+
+			By("restarting the watchers with the new client of a cluster")
+			second := fake.NewSimpleDynamicClientWithCustomListKinds(&scheme, listKinds)
+			factory.StartWatchingClusterGVRs("cluster/lab", second, []schema.GroupVersionResource{otherGVR}, cb.Callback)
+			Expect(lab.stopped).To(BeTrue())
+			Expect(factory.clusters["cluster/lab"].dynamicClient).To(BeIdenticalTo(second))
+			Expect(factory.clusters["cluster/lab"].factory.watchedGVRs()).To(ConsistOf(gvr, otherGVR))
+
+			By("stopping the watchers of a cluster")
+			prod := factory.clusters["cluster/prod"].factory
+			factory.StopWatchingCluster("cluster/prod")
+			Expect(prod.stopped).To(BeTrue())
+			Expect(factory.clusters).ToNot(HaveKey("cluster/prod"))
+			prod.StartWatchingGVRs(first, []schema.GroupVersionResource{otherGVR}, cb.Callback)
+			Expect(prod.watchedGVRs()).To(ConsistOf(gvr))
+
+			factory.StopWatchingCluster("cluster/lab")
+		})
+	})
+
 	Describe("EventHandler AddFunc", func() {
 		It("should handle tombstone unstructured object", func() {
 			cb := new(callbackMock)
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	"github.com/infrahub-operator/vidra/internal/domain"
+)
+
+// artifactNameMatcher returns a matcher for the nameRegex or nameGlob of the selector, nil if no name pattern is set
+func artifactNameMatcher(selector *infrahubv1alpha1.ArtifactSelector) (func(name string) bool, error) {
+	switch {
+	case selector == nil:
+		return nil, nil
+	case selector.NameRegex != "":
+		re, err := regexp.Compile(selector.NameRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nameRegex: %w", err)
+		}
+		return re.MatchString, nil
+	case selector.NameGlob != "":
+		if _, err := path.Match(selector.NameGlob, ""); err != nil {
+			return nil, fmt.Errorf("invalid nameGlob: %w", err)
+		}
+		return func(name string) bool {
+			matched, _ := path.Match(selector.NameGlob, name)
+			return matched
+		}, nil
+	}
+	return nil, nil
+}
+
+// selectArtifacts returns the artifacts whose name is accepted by the matcher, all artifacts if the matcher is nil
+func selectArtifacts(artifacts *[]domain.Artifact, matchName func(name string) bool) *[]domain.Artifact {
+	if matchName == nil || artifacts == nil {
+		return artifacts
+	}
+	selected := make([]domain.Artifact, 0, len(*artifacts))
+	for _, artifact := range *artifacts {
+		if matchName(artifact.Name) {
+			selected = append(selected, artifact)
+		}
+	}
+	return &selected
+}
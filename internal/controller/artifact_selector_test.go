@@ -0,0 +1,52 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	"github.com/infrahub-operator/vidra/internal/domain"
+)
+
+var _ = Describe("Artifact selector", func() {
+	artifacts := &[]domain.Artifact{
+		{ID: "1", Name: "leaf-01-config"},
+		{ID: "2", Name: "leaf-02-config"},
+		{ID: "3", Name: "spine-01-config"},
+	}
+
+	ids := func(artifacts *[]domain.Artifact) []string {
+		var result []string
+		for _, artifact := range *artifacts {
+			result = append(result, artifact.ID)
+		}
+		return result
+	}
+
+	It("should keep all artifacts without a name pattern", func() {
+		for _, selector := range []*infrahubv1alpha1.ArtifactSelector{nil, {TargetGroup: "leafs"}} {
+			matchName, err := artifactNameMatcher(selector)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selectArtifacts(artifacts, matchName)).To(BeIdenticalTo(artifacts))
+		}
+	})
+
+	It("should select the artifacts matching the regex", func() {
+		matchName, err := artifactNameMatcher(&infrahubv1alpha1.ArtifactSelector{NameRegex: "^leaf-.*-config$"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ids(selectArtifacts(artifacts, matchName))).To(Equal([]string{"1", "2"}))
+	})
+
+	It("should select the artifacts matching the glob", func() {
+		matchName, err := artifactNameMatcher(&infrahubv1alpha1.ArtifactSelector{NameGlob: "spine-*"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ids(selectArtifacts(artifacts, matchName))).To(Equal([]string{"3"}))
+	})
+
+	It("should reject invalid patterns", func() {
+		_, err := artifactNameMatcher(&infrahubv1alpha1.ArtifactSelector{NameRegex: "leaf-("})
+		Expect(err).To(MatchError(ContainSubstring("invalid nameRegex")))
+		_, err = artifactNameMatcher(&infrahubv1alpha1.ArtifactSelector{NameGlob: "leaf-["})
+		Expect(err).To(MatchError(ContainSubstring("invalid nameGlob")))
+	})
+})
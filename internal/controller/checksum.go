@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"crypto/md5" //nolint:gosec // Infrahub reports MD5 checksums of artifacts, used for integrity only
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// verifyChecksum compares the content with the checksum reported by Infrahub, an MD5 (Infrahub default) or SHA-256
+// hex digest. A checksum in another format cannot be verified and is reported as not verified.
+func verifyChecksum(content []byte, checksum string) (bool, error) {
+	var h hash.Hash
+	switch len(checksum) {
+	case hex.EncodedLen(md5.Size):
+		h = md5.New() //nolint:gosec
+	case hex.EncodedLen(sha256.Size):
+		h = sha256.New()
+	default:
+		return false, nil
+	}
+	if _, err := hex.DecodeString(checksum); err != nil {
+		return false, nil
+	}
+
+	h.Write(content)
+	if actual := hex.EncodeToString(h.Sum(nil)); actual != strings.ToLower(checksum) {
+		return true, fmt.Errorf("checksum mismatch: Infrahub reported %s, downloaded content has %s (%d bytes)",
+			checksum, actual, len(content))
+	}
+	return true, nil
+}
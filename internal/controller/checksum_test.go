@@ -0,0 +1,37 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Artifact checksum", func() {
+	content := []byte("apiVersion: v1\nkind: ConfigMap\n")
+
+	It("should accept content matching an MD5 or SHA-256 checksum", func() {
+		for _, checksum := range []string{
+			"09dbf6ade1b4036ab4f95f9fe03771a6",
+			"09DBF6ADE1B4036AB4F95F9FE03771A6",
+			"60146ce71c6ba57f4a4679a337a62b4c8df359d0619ea8a4f8057f2270ea0afe",
+		} {
+			verified, err := verifyChecksum(content, checksum)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(verified).To(BeTrue())
+		}
+	})
+
+	It("should reject truncated content", func() {
+		verified, err := verifyChecksum(content[:10], "09dbf6ade1b4036ab4f95f9fe03771a6")
+		Expect(verified).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("checksum mismatch: Infrahub reported 09dbf6ade1b4036ab4f95f9fe03771a6")))
+		Expect(err).To(MatchError(ContainSubstring("(10 bytes)")))
+	})
+
+	It("should not verify checksums of an unknown format", func() {
+		for _, checksum := range []string{"checksum-789", "zz09dbf6ade1b4036ab4f95f9fe03771"} {
+			verified, err := verifyChecksum(content, checksum)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(verified).To(BeFalse())
+		}
+	})
+})
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	"github.com/infrahub-operator/vidra/internal/adapter/k8s"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// localServer is the API server address of the cluster the operator runs in
+const localServer = "https://kubernetes.default.svc"
+
+// resolveCluster returns the VidraCluster referenced by name or selected by labels in the destination,
+// nil if the destination references none
+func resolveCluster(ctx context.Context, c client.Reader, dest infrahubv1alpha1.InfrahubSyncDestination) (*infrahubv1alpha1.VidraCluster, error) {
+	if dest.Cluster != "" {
+		cluster := &infrahubv1alpha1.VidraCluster{}
+		if err := c.Get(ctx, types.NamespacedName{Name: dest.Cluster}, cluster); err != nil {
+			return nil, fmt.Errorf("failed to get VidraCluster %s: %w", dest.Cluster, err)
+		}
+		return cluster, nil
+	}
+	if dest.ClusterSelector == nil {
+		return nil, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(dest.ClusterSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster selector: %w", err)
+	}
+	var clusters infrahubv1alpha1.VidraClusterList
+	if err := c.List(ctx, &clusters, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list VidraClusters: %w", err)
+	}
+	switch len(clusters.Items) {
+	case 0:
+		return nil, fmt.Errorf("no VidraCluster matches the cluster selector %s", selector)
+	case 1:
+		return &clusters.Items[0], nil
+	}
+	names := make([]string, 0, len(clusters.Items))
+	for _, cluster := range clusters.Items {
+		names = append(names, cluster.Name)
+	}
+	return nil, fmt.Errorf("cluster selector %s matches more than one VidraCluster: %s", selector, strings.Join(names, ", "))
+}
+
+// destinationClient returns the client of the cluster the VidraResource deploys to and the name of that cluster
+// for its watchers, "cluster/<name>" for a VidraCluster and "server/<url>" for a server. Without a VidraCluster or
+// server the local client and an empty name are returned.
+func (r *VidraResourceReconciler) destinationClient(ctx context.Context, res *infrahubv1alpha1.VidraResource) (client.Client, string, error) {
+	logger := log.FromContext(ctx)
+	dest := res.Spec.Destination
+
+	cluster, err := resolveCluster(ctx, r.Client, dest)
+	if err != nil {
+		return nil, "", err
+	}
+	if cluster != nil {
+		logger.Info("Using client of VidraCluster for destination", "cluster", cluster.Name)
+		destClient, err := r.DynamicMulticlusterFactory.GetClientForCluster(ctx, cluster, r.Client)
+		return destClient, "cluster/" + cluster.Name, err
+	}
+
+	if dest.Server == "" || dest.Server == localServer {
+		logger.Info("Using local client for destination")
+		return r.Client, "", nil
+	}
+	logger.Info("Using cached client for destination", "server", dest.Server)
+	destClient, err := r.DynamicMulticlusterFactory.GetCachedClientFor(ctx, dest.Server, r.Client)
+	return destClient, "server/" + dest.Server, err
+}
+
+// restMapperFor returns the RESTMapper of the cluster of the destination client. Clients of remote clusters map
+// with the discovery of their cluster, which is refreshed once a kind is missing.
+func (r *VidraResourceReconciler) restMapperFor(destClient client.Client) meta.RESTMapper {
+	if destClient == r.Client {
+		return r.RESTMapper
+	}
+	return destClient.RESTMapper()
+}
+
+// resourcesUsingKubeconfig drops the cached clients of the server of a changed kubeconfig Secret and maps it to the
+// VidraResources deploying to that server, so rotated credentials are used right away
+func (r *VidraResourceReconciler) resourcesUsingKubeconfig(ctx context.Context, obj client.Object) []reconcile.Request {
+	host := obj.GetLabels()[k8s.ClusterKubeconfigLabel]
+	r.DynamicMulticlusterFactory.EvictServer(host)
+
+	var resources infrahubv1alpha1.VidraResourceList
+	if err := r.List(ctx, &resources); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list VidraResources")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, res := range resources.Items {
+		dest := res.Spec.Destination
+		if dest.Cluster == "" && dest.ClusterSelector == nil && dest.Server != "" && k8s.ServerHost(dest.Server) == host {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: res.Name}})
+		}
+	}
+	return requests
+}
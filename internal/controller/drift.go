@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// maxDiffEntries limits the field-level diff summary reported per drifted resource
+const maxDiffEntries = 20
+
+// manifestHash returns a stable hash of the manifest document, used to tell manifest changes from drift.
+func manifestHash(obj *unstructured.Unstructured) (string, error) {
+	// JSON encoding sorts map keys, so the hash does not depend on the order of the document
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash manifest of %s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// fieldDiff summarizes the fields of desired which are missing or different in existing, like isSubset compares them.
+func fieldDiff(path string, desired, existing interface{}) []string {
+	var diffs []string
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		e, ok := existing.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: want object, got %v", displayPath(path), existing)}
+		}
+		keys := make([]string, 0, len(d))
+		for key := range d {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			existingValue, found := e[key]
+			if !found {
+				if d[key] != nil {
+					diffs = append(diffs, fmt.Sprintf("%s: missing", joinPath(path, key)))
+				}
+				continue
+			}
+			diffs = append(diffs, fieldDiff(joinPath(path, key), d[key], existingValue)...)
+		}
+	case []interface{}:
+		e, ok := existing.([]interface{})
+		if !ok || len(d) != len(e) {
+			return []string{fmt.Sprintf("%s: want %d items, got %d", displayPath(path), len(d), len(e))}
+		}
+		for i := range d {
+			diffs = append(diffs, fieldDiff(fmt.Sprintf("%s[%d]", path, i), d[i], e[i])...)
+		}
+	default:
+		if !equality.Semantic.DeepEqual(desired, existing) {
+			diffs = append(diffs, fmt.Sprintf("%s: want %v, got %v", displayPath(path), desired, existing))
+		}
+	}
+	if len(diffs) > maxDiffEntries {
+		diffs = append(diffs[:maxDiffEntries], fmt.Sprintf("... and %d more", len(diffs)-maxDiffEntries))
+	}
+	return diffs
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "."
+	}
+	return strings.TrimPrefix(path, ".")
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	"github.com/infrahub-operator/vidra/internal/adapter/k8s"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// healthRequeueInterval is used to requeue resources which are not yet healthy, to keep their health up to date
+const healthRequeueInterval = 30 * time.Second
+
+// assessHealth checks the health of every managed resource in the destination cluster.
+// It returns a copy of the managed resources with their health and the aggregated health.
+func (r *VidraResourceReconciler) assessHealth(
+	ctx context.Context,
+	managed []infrahubv1alpha1.ManagedResourceStatus,
+	destClient client.Client,
+) ([]infrahubv1alpha1.ManagedResourceStatus, infrahubv1alpha1.HealthStatus, string) {
+	logger := log.FromContext(ctx)
+	checker := r.HealthChecker
+	if checker == nil {
+		checker = k8s.NewHealthChecker()
+	}
+
+	assessed := make([]infrahubv1alpha1.ManagedResourceStatus, 0, len(managed))
+	states := make([]infrahubv1alpha1.HealthStatus, 0, len(managed))
+	var messages []string
+	for _, mr := range managed {
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(schema.FromAPIVersionAndKind(mr.APIVersion, mr.Kind))
+		err := destClient.Get(ctx, client.ObjectKey{Name: mr.Name, Namespace: mr.Namespace}, live)
+		switch {
+		case errors.IsNotFound(err):
+			mr.Health, mr.HealthMessage = infrahubv1alpha1.HealthDegraded, "resource not found in destination cluster"
+		case err != nil:
+			logger.Error(err, "Failed to get resource for health check", "kind", mr.Kind, "name", mr.Name)
+			mr.Health, mr.HealthMessage = infrahubv1alpha1.HealthUnknown, fmt.Sprintf("failed to get resource: %v", err)
+		default:
+			mr.Health, mr.HealthMessage = checker.Check(live)
+		}
+		if mr.Health != infrahubv1alpha1.HealthHealthy {
+			messages = append(messages, fmt.Sprintf("%s %s: %s", mr.Kind, mr.Name, mr.HealthMessage))
+		}
+		assessed = append(assessed, mr)
+		states = append(states, mr.Health)
+	}
+	return assessed, k8s.AggregateHealth(states...), strings.Join(messages, "; ")
+}
+
+// aggregateSyncHealth rolls up the health of all VidraResources controlled by the InfrahubSync.
+func aggregateSyncHealth(ctx context.Context, c client.Client, sync *infrahubv1alpha1.InfrahubSync) (infrahubv1alpha1.HealthStatus, string, error) {
+	var resourceList infrahubv1alpha1.VidraResourceList
+	if err := c.List(ctx, &resourceList); err != nil {
+		return infrahubv1alpha1.HealthUnknown, "", fmt.Errorf("failed to list VidraResources: %w", err)
+	}
+
+	var states []infrahubv1alpha1.HealthStatus
+	var unhealthy []string
+	for i := range resourceList.Items {
+		res := &resourceList.Items[i]
+		if !metav1.IsControlledBy(res, sync) {
+			continue
+		}
+		states = append(states, res.Status.Health)
+		if res.Status.Health != infrahubv1alpha1.HealthHealthy {
+			unhealthy = append(unhealthy, res.Name)
+		}
+	}
+	if len(unhealthy) == 0 {
+		return k8s.AggregateHealth(states...), "", nil
+	}
+	return k8s.AggregateHealth(states...), fmt.Sprintf("not healthy: %s", strings.Join(unhealthy, ", ")), nil
+}
+
+// setHealthCondition sets the Healthy condition matching the aggregated health of the resource.
+func setHealthCondition(res client.Object, health infrahubv1alpha1.HealthStatus, message string) {
+	if health == infrahubv1alpha1.HealthHealthy {
+		SetCondition(res, infrahubv1alpha1.ConditionHealthy, metav1.ConditionTrue, string(health), "All resources are healthy")
+		return
+	}
+	SetCondition(res, infrahubv1alpha1.ConditionHealthy, metav1.ConditionFalse, string(health), message)
+}
+
+// updateOwnerHealth rolls up the health of the VidraResource to the InfrahubSync controlling it.
+func (r *VidraResourceReconciler) updateOwnerHealth(ctx context.Context, res *infrahubv1alpha1.VidraResource) error {
+	ownerRef := metav1.GetControllerOf(res)
+	if ownerRef == nil || ownerRef.Kind != "InfrahubSync" {
+		return nil
+	}
+	owner := &infrahubv1alpha1.InfrahubSync{}
+	if err := r.Get(ctx, client.ObjectKey{Name: ownerRef.Name}, owner); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	health, message, err := aggregateSyncHealth(ctx, r.Client, owner)
+	if err != nil {
+		return err
+	}
+	if owner.Status.Health == health {
+		return nil
+	}
+	return MarkState(ctx, r.Client, owner, func() {
+		owner.Status.Health = health
+		setHealthCondition(owner, health, message)
+	})
+}
@@ -0,0 +1,282 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	"github.com/itchyny/gojq"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+)
+
+// removeIgnoredDifferences removes the fields of all matching ignoreDifferences rules from the resource,
+// so they are neither compared with the live resource nor applied.
+func removeIgnoredDifferences(obj *unstructured.Unstructured, rules []infrahubv1alpha1.ResourceIgnoreDifferences) error {
+	for _, rule := range rules {
+		if !ignoreRuleMatches(rule, obj) {
+			continue
+		}
+		for _, pointer := range rule.JSONPointers {
+			if err := removeJSONPointer(obj.Object, pointer); err != nil {
+				return err
+			}
+		}
+		for _, expression := range rule.JQPathExpressions {
+			if err := removeJQPath(obj, expression); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func ignoreRuleMatches(rule infrahubv1alpha1.ResourceIgnoreDifferences, obj *unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+	return rule.Group == gvk.Group && rule.Kind == gvk.Kind &&
+		(rule.Name == "" || rule.Name == obj.GetName()) &&
+		(rule.Namespace == "" || rule.Namespace == obj.GetNamespace())
+}
+
+// keepIgnoredLiveValues sets the fields of all matching ignoreDifferences rules to their value on the live resource.
+// Server-side apply deletes fields which were applied before and are missing from the apply body, so an ignored field
+// is applied with its live value instead of being removed from the resource.
+func keepIgnoredLiveValues(obj, live *unstructured.Unstructured, rules []infrahubv1alpha1.ResourceIgnoreDifferences) error {
+	var paths [][]interface{}
+	for _, rule := range rules {
+		if !ignoreRuleMatches(rule, obj) {
+			continue
+		}
+		for _, pointer := range rule.JSONPointers {
+			tokens, err := parseJSONPointer(pointer)
+			if err != nil {
+				return err
+			}
+			if path, ok := resolvePath(live.Object, tokens); ok {
+				paths = append(paths, path)
+			}
+		}
+		for _, expression := range rule.JQPathExpressions {
+			jqPaths, err := findJQPaths(live, expression)
+			if err != nil {
+				return err
+			}
+			paths = append(paths, jqPaths...)
+		}
+	}
+	for _, path := range paths {
+		if value, ok := valueAtPath(live.Object, path); ok {
+			setAtPath(obj.Object, path, runtime.DeepCopyJSONValue(value))
+		}
+	}
+	return nil
+}
+
+// removeJSONPointer removes the field referenced by the JSON pointer (RFC 6901), missing fields are skipped.
+func removeJSONPointer(object map[string]interface{}, pointer string) error {
+	tokens, err := parseJSONPointer(pointer)
+	if err != nil {
+		return err
+	}
+	removeAtPath(object, tokens)
+	return nil
+}
+
+// parseJSONPointer returns the unescaped reference tokens of the JSON pointer
+func parseJSONPointer(pointer string) ([]string, error) {
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must start with /", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(tokens[i])
+	}
+	return tokens, nil
+}
+
+// resolvePath returns the path of the reference tokens below the node with list indexes as int,
+// it reports false if the field does not exist.
+func resolvePath(node interface{}, tokens []string) ([]interface{}, bool) {
+	path := make([]interface{}, 0, len(tokens))
+	for _, token := range tokens {
+		switch current := node.(type) {
+		case map[string]interface{}:
+			child, ok := current[token]
+			if !ok {
+				return nil, false
+			}
+			path, node = append(path, token), child
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(current) {
+				return nil, false
+			}
+			path, node = append(path, index), current[index]
+		default:
+			return nil, false
+		}
+	}
+	return path, true
+}
+
+// valueAtPath returns the value at the path below the node
+func valueAtPath(node interface{}, path []interface{}) (interface{}, bool) {
+	for _, key := range path {
+		switch current := node.(type) {
+		case map[string]interface{}:
+			name, isName := key.(string)
+			child, ok := current[name]
+			if !isName || !ok {
+				return nil, false
+			}
+			node = child
+		case []interface{}:
+			index, isIndex := key.(int)
+			if !isIndex || index < 0 || index >= len(current) {
+				return nil, false
+			}
+			node = current[index]
+		default:
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// setAtPath sets the value at the path below the node and returns the node. Missing maps and lists are created,
+// a list is only extended by the element right after its end.
+func setAtPath(node interface{}, path []interface{}, value interface{}) interface{} {
+	if len(path) == 0 {
+		return value
+	}
+	switch key := path[0].(type) {
+	case string:
+		current, ok := node.(map[string]interface{})
+		if node == nil {
+			current, ok = map[string]interface{}{}, true
+		}
+		if !ok {
+			return node
+		}
+		current[key] = setAtPath(current[key], path[1:], value)
+		return current
+	case int:
+		current, ok := node.([]interface{})
+		if node == nil {
+			current, ok = []interface{}{}, true
+		}
+		if !ok || key < 0 || key > len(current) {
+			return node
+		}
+		if key == len(current) {
+			return append(current, setAtPath(nil, path[1:], value))
+		}
+		current[key] = setAtPath(current[key], path[1:], value)
+		return current
+	}
+	return node
+}
+
+// removeAtPath removes the value at the path below the node and returns the node, lists are shortened.
+func removeAtPath(node interface{}, tokens []string) interface{} {
+	token := tokens[0]
+	switch current := node.(type) {
+	case map[string]interface{}:
+		if len(tokens) == 1 {
+			delete(current, token)
+		} else if child, ok := current[token]; ok {
+			current[token] = removeAtPath(child, tokens[1:])
+		}
+		return current
+	case []interface{}:
+		index, err := strconv.Atoi(token)
+		if err != nil || index < 0 || index >= len(current) {
+			return current
+		}
+		if len(tokens) == 1 {
+			return append(current[:index:index], current[index+1:]...)
+		}
+		current[index] = removeAtPath(current[index], tokens[1:])
+		return current
+	}
+	return node
+}
+
+// findJQPaths returns all paths below the resource matched by the JQ path expression.
+func findJQPaths(obj *unstructured.Unstructured, expression string) ([][]interface{}, error) {
+	query, err := gojq.Parse(fmt.Sprintf("path(%s)", expression))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JQ path expression %q: %w", expression, err)
+	}
+	input, err := jqInput(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths [][]interface{}
+	iter := query.Run(input)
+	for {
+		result, ok := iter.Next()
+		if !ok {
+			return paths, nil
+		}
+		if err, isErr := result.(error); isErr {
+			return nil, fmt.Errorf("failed to evaluate JQ path expression %q: %w", expression, err)
+		}
+		if path, isPath := result.([]interface{}); isPath {
+			paths = append(paths, path)
+		}
+	}
+}
+
+// jqInput returns the resource as plain JSON types, the only types supported by gojq
+func jqInput(obj *unstructured.Unstructured) (interface{}, error) {
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return nil, err
+	}
+	var input interface{}
+	if err := json.Unmarshal(data, &input); err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// removeJQPath deletes all paths matched by the JQ path expression.
+func removeJQPath(obj *unstructured.Unstructured, expression string) error {
+	query, err := gojq.Parse(fmt.Sprintf("del(%s)", expression))
+	if err != nil {
+		return fmt.Errorf("invalid JQ path expression %q: %w", expression, err)
+	}
+	input, err := jqInput(obj)
+	if err != nil {
+		return err
+	}
+
+	iter := query.Run(input)
+	result, ok := iter.Next()
+	if !ok {
+		return nil
+	}
+	if err, isErr := result.(error); isErr {
+		return fmt.Errorf("failed to evaluate JQ path expression %q: %w", expression, err)
+	}
+	if _, isMap := result.(map[string]interface{}); !isMap {
+		return fmt.Errorf("JQ path expression %q did not return an object", expression)
+	}
+
+	// Decode the result back with int64 numbers like all other unstructured objects
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	object := map[string]interface{}{}
+	if err := utiljson.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	obj.Object = object
+	return nil
+}
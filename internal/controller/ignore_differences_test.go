@@ -0,0 +1,113 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+)
+
+var _ = Describe("removeIgnoredDifferences", func() {
+	var deployment *unstructured.Unstructured
+
+	BeforeEach(func() {
+		deployment = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata":   map[string]interface{}{"name": "web", "namespace": "default"},
+			"spec": map[string]interface{}{
+				"replicas": int64(3),
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{"name": "web", "image": "nginx"},
+							map[string]interface{}{"name": "sidecar", "image": "envoy"},
+						},
+					},
+				},
+			},
+		}}
+	})
+
+	It("should remove the fields referenced by JSON pointers", func() {
+		Expect(removeIgnoredDifferences(deployment, []infrahubv1alpha1.ResourceIgnoreDifferences{{
+			Group:        "apps",
+			Kind:         "Deployment",
+			JSONPointers: []string{"/spec/replicas", "/spec/template/spec/containers/1", "/spec/missing/field"},
+		}})).To(Succeed())
+
+		_, found, _ := unstructured.NestedInt64(deployment.Object, "spec", "replicas")
+		Expect(found).To(BeFalse())
+		containers, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+		Expect(containers).To(HaveLen(1))
+	})
+
+	It("should remove the fields matched by JQ path expressions and keep int64 numbers", func() {
+		Expect(removeIgnoredDifferences(deployment, []infrahubv1alpha1.ResourceIgnoreDifferences{{
+			Group:             "apps",
+			Kind:              "Deployment",
+			Name:              "web",
+			JQPathExpressions: []string{`.spec.template.spec.containers[] | select(.name == "sidecar")`},
+		}})).To(Succeed())
+
+		containers, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+		Expect(containers).To(HaveLen(1))
+		Expect(containers[0].(map[string]interface{})["name"]).To(Equal("web"))
+		Expect(deployment.Object["spec"].(map[string]interface{})["replicas"]).To(Equal(int64(3)))
+	})
+
+	It("should skip rules which do not match the resource", func() {
+		Expect(removeIgnoredDifferences(deployment, []infrahubv1alpha1.ResourceIgnoreDifferences{
+			{Group: "apps", Kind: "StatefulSet", JSONPointers: []string{"/spec/replicas"}},
+			{Group: "apps", Kind: "Deployment", Name: "other", JSONPointers: []string{"/spec/replicas"}},
+			{Group: "apps", Kind: "Deployment", Namespace: "kube-system", JSONPointers: []string{"/spec/replicas"}},
+			{Kind: "Deployment", JSONPointers: []string{"/spec/replicas"}},
+		})).To(Succeed())
+
+		Expect(deployment.Object["spec"].(map[string]interface{})["replicas"]).To(Equal(int64(3)))
+	})
+
+	It("should reject invalid JSON pointers and JQ expressions", func() {
+		Expect(removeIgnoredDifferences(deployment, []infrahubv1alpha1.ResourceIgnoreDifferences{{
+			Group: "apps", Kind: "Deployment", JSONPointers: []string{"spec/replicas"},
+		}})).To(MatchError(ContainSubstring("invalid JSON pointer")))
+		Expect(removeIgnoredDifferences(deployment, []infrahubv1alpha1.ResourceIgnoreDifferences{{
+			Group: "apps", Kind: "Deployment", JQPathExpressions: []string{".spec.["},
+		}})).To(MatchError(ContainSubstring("invalid JQ path expression")))
+	})
+
+	It("should keep the live values of ignored fields which were applied before", func() {
+		live := deployment.DeepCopy()
+		Expect(unstructured.SetNestedField(live.Object, int64(5), "spec", "replicas")).To(Succeed())
+
+		rules := []infrahubv1alpha1.ResourceIgnoreDifferences{{
+			Group:             "apps",
+			Kind:              "Deployment",
+			JSONPointers:      []string{"/spec/replicas", "/spec/missing/field"},
+			JQPathExpressions: []string{`.spec.template.spec.containers[] | select(.name == "sidecar")`},
+		}}
+		Expect(removeIgnoredDifferences(deployment, rules)).To(Succeed())
+		Expect(keepIgnoredLiveValues(deployment, live, rules)).To(Succeed())
+
+		Expect(deployment.Object["spec"].(map[string]interface{})["replicas"]).To(Equal(int64(5)))
+		containers, _, _ := unstructured.NestedSlice(deployment.Object, "spec", "template", "spec", "containers")
+		Expect(containers).To(HaveLen(2))
+		Expect(containers[1].(map[string]interface{})["image"]).To(Equal("envoy"))
+		_, found, _ := unstructured.NestedFieldNoCopy(deployment.Object, "spec", "missing")
+		Expect(found).To(BeFalse())
+	})
+
+	It("should leave ignored fields out if the live resource does not set them", func() {
+		live := deployment.DeepCopy()
+		unstructured.RemoveNestedField(live.Object, "spec", "replicas")
+
+		rules := []infrahubv1alpha1.ResourceIgnoreDifferences{{Group: "apps", Kind: "Deployment", JSONPointers: []string{"/spec/replicas"}}}
+		Expect(removeIgnoredDifferences(deployment, rules)).To(Succeed())
+		Expect(keepIgnoredLiveValues(deployment, live, rules)).To(Succeed())
+
+		_, found, _ := unstructured.NestedInt64(deployment.Object, "spec", "replicas")
+		Expect(found).To(BeFalse())
+	})
+})
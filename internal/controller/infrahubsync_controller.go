@@ -21,33 +21,80 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 	"time"
 
 	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/infrahub-operator/vidra/internal/adapter/infrahub"
 	"github.com/infrahub-operator/vidra/internal/adapter/k8s"
 	"github.com/infrahub-operator/vidra/internal/domain"
 )
 
+// configKeys are the keys of the operator ConfigMap read by InitConfigWithClient
+var configKeys = map[string]struct{}{
+	"requeueSyncAfter":       {},
+	"queryName":              {},
+	"infrahubMaxRetries":     {},
+	"infrahubRetryBackoff":   {},
+	"infrahubRequestTimeout": {},
+	"infrahubRateLimit":      {},
+	"infrahubRateBurst":      {},
+	"infrahubMaxInFlight":    {},
+	"maxArtifactSize":        {},
+	"inlineManifestLimit":    {},
+	"manifestChunkNamespace": {},
+}
+
+// hasConfigKey reports whether the data sets any of the known config keys
+func hasConfigKey(data map[string]string) bool {
+	for key, value := range data {
+		if _, ok := configKeys[key]; ok && value != "" {
+			return true
+		}
+	}
+	return false
+}
+
 type InfrahubSyncReconciler struct {
 	client.Client
 	Scheme         *runtime.Scheme
 	RequeueAfter   time.Duration
 	QueryName      string
 	InfrahubClient domain.InfrahubClient
+	// ClientOptions configures the retries and request timeout of the Infrahub client
+	ClientOptions infrahub.ClientOptions
+	// WebhookEvents triggers immediate syncs, e.g. from the WebhookReceiver
+	WebhookEvents <-chan event.GenericEvent
+	// MaxArtifactSize limits the size of a downloaded artifact in bytes (default: DefaultMaxArtifactSize)
+	MaxArtifactSize int64
+	// InlineManifestLimit is the size up to which manifests are stored inline, larger manifests are stored in
+	// chunk Secrets in ManifestChunkNamespace (default: DefaultInlineManifestLimit, DefaultManifestChunkNamespace)
+	InlineManifestLimit    int64
+	ManifestChunkNamespace string
+
+	// retryLimiter requeues failed syncs with the backoff of their retry strategy
+	retryLimiter *retryRateLimiter
 }
 
 // +kubebuilder:rbac:groups=infrahub.operators.com,resources=infrahubsyncs,verbs=get;list;watch;create;update;patch;delete
@@ -56,8 +103,9 @@ type InfrahubSyncReconciler struct {
 // +kubebuilder:rbac:groups=infrahub.operators.com,resources=infrahubresources,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrahub.operators.com,resources=infrahubresources/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=infrahub.operators.com,resources=infrahubresources/finalizers,verbs=update
-// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
-// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get
+// Manifests above the inline limit are written to chunk Secrets, which are replaced and deleted with the manifest
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 
 // Reconcile reconciles the InfrahubSync resource.
 // controller/infrahubsync_controller.go
@@ -76,9 +124,27 @@ func (r *InfrahubSyncReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{RequeueAfter: r.RequeueAfter}, MarkStateFailed(ctx, r.Client, infrahubSync, err)
 	}
 
+	if infrahubSync.Spec.Suspend || meta.IsStatusConditionTrue(infrahubSync.Status.Conditions, infrahubv1alpha1.ConditionSuspended) {
+		// Suspend or resume the VidraResources together with the sync
+		if err := r.setVidraResourcesSuspended(ctx, infrahubSync, infrahubSync.Spec.Suspend); err != nil {
+			logger.Error(err, "Failed to propagate suspend to VidraResources")
+			return r.failSync(ctx, req, infrahubSync, err)
+		}
+	}
+	if infrahubSync.Spec.Suspend {
+		logger.Info("InfrahubSync is suspended, skipping query")
+		return ctrl.Result{}, MarkState(ctx, r.Client, infrahubSync, func() {
+			MarkSuspended(infrahubSync, true)
+			MarkObserved(infrahubSync)
+			infrahubSync.Status.NextSyncTime = metav1.Time{}
+		})
+	}
+
 	// Mark the InfrahubSync resource as running
 	if err := MarkState(ctx, r.Client, infrahubSync, func() {
 		infrahubSync.Status.SyncState = infrahubv1alpha1.StateRunning
+		MarkReconciling(infrahubSync)
+		MarkSuspended(infrahubSync, false)
 	}); err != nil {
 		logger.Error(err, "Failed to update SyncState to Running")
 		return ctrl.Result{RequeueAfter: r.RequeueAfter}, err
@@ -87,93 +153,257 @@ func (r *InfrahubSyncReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	var apiURL = infrahubSync.Spec.Source.InfrahubAPIURL
 
 	// Get authentication credentials from Kubernetes Secret
-	username, password, err := r.getCredentials(ctx, apiURL)
+	credentials, err := r.getCredentials(ctx, apiURL)
 	if err != nil {
 		logger.Error(err, "Failed to get credentials from Secret")
-		return ctrl.Result{RequeueAfter: r.RequeueAfter}, MarkStateFailed(ctx, r.Client, infrahubSync, err)
+		return r.failSync(ctx, req, infrahubSync, NewConditionError(
+			infrahubv1alpha1.ConditionCredentialsResolved, infrahubv1alpha1.ReasonCredentialsNotFound, err))
+	}
+
+	// Use the TLS and proxy settings of the sync for all requests to Infrahub
+	connection, err := r.getConnection(ctx, infrahubSync)
+	if err != nil {
+		logger.Error(err, "Failed to get the connection settings")
+		return r.failSync(ctx, req, infrahubSync, NewConditionError(
+			infrahubv1alpha1.ConditionInfrahubReachable, infrahubv1alpha1.ReasonInvalidConnection, err))
+	}
+	infrahubClient, err := r.InfrahubClient.WithConnection(connection)
+	if err != nil {
+		logger.Error(err, "Invalid connection settings")
+		return r.failSync(ctx, req, infrahubSync, NewConditionError(
+			infrahubv1alpha1.ConditionInfrahubReachable, infrahubv1alpha1.ReasonInvalidConnection, err))
+	}
+
+	// The name pattern of the selector is checked before querying Infrahub
+	matchName, err := artifactNameMatcher(infrahubSync.Spec.Source.Selector)
+	if err != nil {
+		logger.Error(err, "Invalid artifact selector")
+		return r.failSync(ctx, req, infrahubSync, NewConditionError(
+			infrahubv1alpha1.ConditionArtifactsFetched, infrahubv1alpha1.ReasonInvalidSelector, err))
 	}
 
 	// Get authentication token using the Infrahub client
-	token, err := r.InfrahubClient.Login(apiURL, username, password)
+	token, err := infrahubClient.Login(ctx, apiURL, credentials)
 	if err != nil {
 		logger.Error(err, "Failed to login to Infrahub")
-		return ctrl.Result{RequeueAfter: r.RequeueAfter}, MarkStateFailed(ctx, r.Client, infrahubSync, err)
+		return r.failSync(ctx, req, infrahubSync, NewConditionError(
+			infrahubv1alpha1.ConditionInfrahubReachable, infrahubv1alpha1.ReasonLoginFailed, err))
 	}
 
 	// Run the query and process the results using the Infrahub client
-	queryResult, err := r.InfrahubClient.RunQuery(
-		r.QueryName,
-		apiURL,
-		infrahubSync.Spec.Source.ArtifactName,
-		infrahubSync.Spec.Source.TargetBranch,
-		infrahubSync.Spec.Source.TargetDate,
-		token)
+	queryResult, err := r.queryArtifacts(ctx, infrahubClient, infrahubSync, apiURL, token)
 	if err != nil {
 		logger.Error(err, "Failed to execute query")
-		return ctrl.Result{}, MarkStateFailed(ctx, r.Client, infrahubSync, err)
+		return r.failSync(ctx, req, infrahubSync, NewConditionError(
+			infrahubv1alpha1.ConditionInfrahubReachable, infrahubv1alpha1.ReasonQueryFailed, err))
 	}
+	queryResult = selectArtifacts(queryResult, matchName)
 	logger.Info("Query executed successfully", "result", queryResult)
 
 	// Process query results and compare with existing resources
-	err = r.processArtifacts(ctx, infrahubSync, queryResult, token)
+	outcome, syncedArtifacts, err := r.processArtifacts(ctx, infrahubClient, infrahubSync, queryResult, token)
 	if err != nil {
 		logger.Error(err, "Error processing artifacts")
-		return ctrl.Result{RequeueAfter: r.RequeueAfter}, MarkStateFailed(ctx, r.Client, infrahubSync, err)
+		return r.failSync(ctx, req, infrahubSync, NewConditionError(
+			infrahubv1alpha1.ConditionArtifactsFetched, infrahubv1alpha1.ReasonSyncFailed, err), func() {
+			// The artifacts are nil if processing failed before any artifact was synced
+			if syncedArtifacts != nil {
+				infrahubSync.Status.Artifacts = syncedArtifacts
+			}
+		})
+	}
+
+	// Roll up the health of the VidraResources created by this sync
+	health, healthMessage, err := aggregateSyncHealth(ctx, r.Client, infrahubSync)
+	if err != nil {
+		logger.Error(err, "Failed to aggregate health of VidraResources")
 	}
 
+	requeueAfter := nextSync(infrahubSync, r.RequeueAfter, 0)
+
 	// Update the status of the InfrahubSync resource
 	if err := MarkState(ctx, r.Client, infrahubSync, func() {
-		infrahubSync.Status.SyncState = infrahubv1alpha1.StateSucceeded
 		infrahubSync.Status.LastSyncTime = metav1.Now()
+		infrahubSync.Status.NextSyncTime = nextSyncTime(requeueAfter)
+		infrahubSync.Status.FailureCount = 0
 		infrahubSync.Status.LastError = ""
+		infrahubSync.Status.Artifacts = syncedArtifacts
+		SetCondition(infrahubSync, infrahubv1alpha1.ConditionCredentialsResolved, metav1.ConditionTrue, infrahubv1alpha1.ReasonSucceeded, "Credentials found")
+		SetCondition(infrahubSync, infrahubv1alpha1.ConditionInfrahubReachable, metav1.ConditionTrue, infrahubv1alpha1.ReasonSucceeded, "Logged in and queried artifacts")
+		SetCondition(infrahubSync, infrahubv1alpha1.ConditionArtifactsFetched, metav1.ConditionTrue, infrahubv1alpha1.ReasonSucceeded,
+			fmt.Sprintf("%d artifacts synced", len(*queryResult)))
+		infrahubSync.Status.SyncState = setPruneConditions(infrahubSync, outcome)
+		infrahubSync.Status.Health = health
+		setHealthCondition(infrahubSync, health, healthMessage)
+		MarkObserved(infrahubSync)
 	}); err != nil {
 		logger.Error(err, "Failed to update SyncState to Success")
 		return ctrl.Result{RequeueAfter: r.RequeueAfter}, err
 	}
 
-	return ctrl.Result{RequeueAfter: r.RequeueAfter}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
-// getCredentials fetches Infrahub API credentials from Kubernetes Secret
-func (r *InfrahubSyncReconciler) getCredentials(ctx context.Context, apiURL string) (string, string, error) {
+// failSync marks the sync as failed and schedules the next attempt with the backoff of its retry strategy.
+// Once the retry limit is exhausted, the sync interval is used again.
+func (r *InfrahubSyncReconciler) failSync(ctx context.Context, req ctrl.Request, infrahubSync *infrahubv1alpha1.InfrahubSync, err error, updateStatus ...func()) (ctrl.Result, error) {
+	failures := infrahubSync.Status.FailureCount + 1
+	requeueAfter := nextSync(infrahubSync, r.RequeueAfter, failures)
+	r.retryLimiter.schedule(req, requeueAfter)
+	return ctrl.Result{RequeueAfter: requeueAfter}, MarkStateFailed(ctx, r.Client, infrahubSync, err, func() {
+		infrahubSync.Status.FailureCount = failures
+		infrahubSync.Status.NextSyncTime = nextSyncTime(requeueAfter)
+		for _, update := range updateStatus {
+			update()
+		}
+	})
+}
+
+// setVidraResourcesSuspended sets spec.suspend of all VidraResources controlled by the sync
+func (r *InfrahubSyncReconciler) setVidraResourcesSuspended(ctx context.Context, infrahubSync *infrahubv1alpha1.InfrahubSync, suspend bool) error {
+	var resourceList infrahubv1alpha1.VidraResourceList
+	if err := r.List(ctx, &resourceList); err != nil {
+		return fmt.Errorf("failed to list VidraResources: %w", err)
+	}
+	for i := range resourceList.Items {
+		res := &resourceList.Items[i]
+		if !metav1.IsControlledBy(res, infrahubSync) || res.Spec.Suspend == suspend {
+			continue
+		}
+		if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			if err := r.Get(ctx, client.ObjectKeyFromObject(res), res); err != nil {
+				return err
+			}
+			res.Spec.Suspend = suspend
+			return r.Update(ctx, res)
+		}); err != nil {
+			return fmt.Errorf("failed to update suspend of VidraResource %s: %w", res.Name, err)
+		}
+	}
+	return nil
+}
+
+// getCredentials fetches Infrahub API credentials from Kubernetes Secret.
+// A Secret with a token is preferred over one with a username and password.
+func (r *InfrahubSyncReconciler) getCredentials(ctx context.Context, apiURL string) (domain.Credentials, error) {
 	secretList := &v1.SecretList{}
 
 	trimmedAPIURL := strings.TrimPrefix(strings.Split(apiURL, ":")[1], "//") // Remove https and port
 	if err := k8s.GetSortedListByLabel(ctx, r.Client, "infrahub-api-url", trimmedAPIURL, secretList); err != nil {
-		return "", "", fmt.Errorf("no secret found with InfrahubAPIURL: %s, error: %w", apiURL, err)
+		return domain.Credentials{}, fmt.Errorf("no secret found with InfrahubAPIURL: %s, error: %w", apiURL, err)
 	}
 
-	var usernameRaw, passwordRaw []byte
+	var credentials domain.Credentials
 	var found bool
 
 	for _, secret := range secretList.Items {
+		if t, tok := secret.Data["token"]; tok {
+			return domain.Credentials{APIToken: string(bytes.TrimSpace(t))}, nil
+		}
+		if found {
+			continue
+		}
 		if u, uok := secret.Data["username"]; uok {
 			if p, pok := secret.Data["password"]; pok {
-				usernameRaw = u
-				passwordRaw = p
+				credentials.Username = string(bytes.TrimSpace(u))
+				credentials.Password = string(bytes.TrimSpace(p))
 				found = true
-				break
 			}
 		}
 	}
 
 	if !found {
-		return "", "", fmt.Errorf("no secret found with both username and password fields")
+		return domain.Credentials{}, fmt.Errorf("no secret found with a token or both username and password fields")
+	}
+
+	return credentials, nil
+}
+
+// queryArtifacts runs the stored query of the operator config or, in GraphQL mode and for selectors, an inline query
+// with the filters of the sync
+func (r *InfrahubSyncReconciler) queryArtifacts(ctx context.Context, infrahubClient domain.InfrahubClient, infrahubSync *infrahubv1alpha1.InfrahubSync, apiURL string, token domain.Token) (*[]domain.Artifact, error) {
+	source := infrahubSync.Spec.Source
+	if source.Selector == nil && (source.Query == nil || source.Query.Mode != infrahubv1alpha1.QueryModeGraphQL) {
+		return infrahubClient.RunQuery(ctx, r.QueryName, apiURL, source.ArtifactName, source.TargetBranch, source.TargetDate, token)
 	}
 
-	username := string(bytes.TrimSpace(usernameRaw))
-	password := string(bytes.TrimSpace(passwordRaw))
+	query := domain.ArtifactQuery{
+		Name:   source.ArtifactName,
+		Branch: source.TargetBranch,
+		At:     source.TargetDate,
+	}
+	if source.Query != nil {
+		query.ObjectIDs = source.Query.ObjectIDs
+		query.Statuses = source.Query.Statuses
+		query.Definition = source.Query.Definition
+		query.PageSize = int(source.Query.PageSize)
+	}
+	if selector := source.Selector; selector != nil {
+		if selector.ArtifactDefinition != "" {
+			query.Definition = selector.ArtifactDefinition
+		}
+		query.TargetGroup = selector.TargetGroup
+	}
+	return infrahubClient.QueryArtifacts(ctx, apiURL, query, token)
+}
+
+// getConnection reads the CA bundle and client certificate referenced in the TLS config of the sync
+func (r *InfrahubSyncReconciler) getConnection(ctx context.Context, infrahubSync *infrahubv1alpha1.InfrahubSync) (domain.Connection, error) {
+	connection := domain.Connection{ProxyURL: infrahubSync.Spec.Source.ProxyURL}
+	tlsConfig := infrahubSync.Spec.Source.TLS
+	if tlsConfig == nil {
+		return connection, nil
+	}
+	connection.InsecureSkipVerify = tlsConfig.InsecureSkipVerify
+
+	if ref := tlsConfig.CABundle; ref != nil {
+		key := ref.Key
+		if key == "" {
+			key = "ca.crt"
+		}
+		name := types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}
+		if ref.Kind == "Secret" {
+			secret := &v1.Secret{}
+			if err := r.Get(ctx, name, secret); err != nil {
+				return connection, fmt.Errorf("failed to get CA bundle Secret %s: %w", name, err)
+			}
+			connection.CABundle = secret.Data[key]
+		} else {
+			configMap := &v1.ConfigMap{}
+			if err := r.Get(ctx, name, configMap); err != nil {
+				return connection, fmt.Errorf("failed to get CA bundle ConfigMap %s: %w", name, err)
+			}
+			connection.CABundle = []byte(configMap.Data[key])
+		}
+		if len(connection.CABundle) == 0 {
+			return connection, fmt.Errorf("CA bundle %s has no key %s", name, key)
+		}
+	}
 
-	return username, password, nil
+	if ref := tlsConfig.ClientCertSecret; ref != nil {
+		name := types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}
+		secret := &v1.Secret{}
+		if err := r.Get(ctx, name, secret); err != nil {
+			return connection, fmt.Errorf("failed to get client certificate Secret %s: %w", name, err)
+		}
+		connection.ClientCert = secret.Data[v1.TLSCertKey]
+		connection.ClientKey = secret.Data[v1.TLSPrivateKeyKey]
+		if len(connection.ClientCert) == 0 || len(connection.ClientKey) == 0 {
+			return connection, fmt.Errorf("client certificate Secret %s needs both %s and %s", name, v1.TLSCertKey, v1.TLSPrivateKeyKey)
+		}
+	}
+	return connection, nil
 }
 
-// processArtifacts processes the artifacts retrieved from Infrahub and syncs resources
+// processArtifacts processes the artifacts retrieved from Infrahub and syncs resources.
+// It returns the synced artifacts for the status, artifacts with an unchanged checksum are not downloaded again.
 func (r *InfrahubSyncReconciler) processArtifacts(
 	ctx context.Context,
+	infrahubClient domain.InfrahubClient,
 	infrahubSync *infrahubv1alpha1.InfrahubSync,
 	artifacts *[]domain.Artifact,
-	token string,
-) error {
+	token domain.Token,
+) (pruneOutcome, []infrahubv1alpha1.SyncedArtifact, error) {
 	log := log.FromContext(ctx)
 
 	log.Info("Processing artifacts", "artifactCount", len(*artifacts))
@@ -189,92 +419,256 @@ func (r *InfrahubSyncReconciler) processArtifacts(
 	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		return r.List(ctx, &resourceList, client.InNamespace(infrahubSync.Namespace))
 	}); err != nil {
-		return fmt.Errorf("failed to list VidraResources: %w", err)
+		return pruneOutcome{}, nil, fmt.Errorf("failed to list VidraResources: %w", err)
 	}
 
-	// Delete stale resources
+	// Only VidraResources created by this sync are pruned
+	var stale []infrahubv1alpha1.VidraResource
+	ownedNames := make(map[string]struct{}, len(resourceList.Items))
 	for _, res := range resourceList.Items {
-		if res.Spec.Destination.Server == infrahubSync.Spec.Destination.Server &&
-			res.Spec.Destination.Namespace == infrahubSync.Spec.Destination.Namespace {
-
-			if _, exists := currentArtifactIDs[res.Name]; !exists {
-				if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-					return r.Delete(ctx, &res)
-				}); err != nil {
-					return fmt.Errorf("failed to delete stale VidraResource %s: %w", res.Name, err)
-				}
-				log.Info("Deleted stale VidraResource", "name", res.Name)
+		if !metav1.IsControlledBy(&res, infrahubSync) {
+			continue
+		}
+		ownedNames[res.Name] = struct{}{}
+		if _, exists := currentArtifactIDs[res.Name]; !exists {
+			stale = append(stale, res)
+		}
+	}
+
+	outcome := checkPrune(infrahubSync.Spec.Destination, len(stale), len(ownedNames), "VidraResources")
+	if outcome.reason != "" {
+		log.Info("Keeping stale VidraResources", "reason", outcome.reason, "message", outcome.message)
+	} else {
+		for _, res := range stale {
+			if pruneDisabled(&res) {
+				log.Info("Skipping deletion of stale VidraResource, pruning disabled by annotation", "name", res.Name)
+				continue
+			}
+			if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+				return r.Delete(ctx, &res)
+			}); err != nil {
+				return outcome, nil, fmt.Errorf("failed to delete stale VidraResource %s: %w", res.Name, err)
 			}
+			log.Info("Deleted stale VidraResource", "name", res.Name)
 		}
 	}
 
-	// Create or update resources for current artifacts
-	for _, artifact := range *artifacts {
+	// Entries of artifacts no longer returned by Infrahub are dropped by only keeping the current artifacts
+	previous := make(map[string]infrahubv1alpha1.SyncedArtifact, len(infrahubSync.Status.Artifacts))
+	for _, entry := range infrahubSync.Status.Artifacts {
+		previous[entry.ArtifactID] = entry
+	}
+	syncedArtifacts := make([]infrahubv1alpha1.SyncedArtifact, 0, len(*artifacts))
 
-		found := false
-		for _, checksum := range infrahubSync.Status.Checksums {
-			if artifact.Checksum == checksum {
-				found = true
-				break
+	// Create or update resources for current artifacts. A failed artifact keeps its previous entry and does not
+	// block the other artifacts, the errors of all failed artifacts are returned together.
+	var errs []error
+	for _, artifact := range *artifacts {
+		entry, known := previous[artifact.ID]
+		_, exists := ownedNames[entry.VidraResourceName]
+
+		synced, err := r.syncArtifact(ctx, infrahubClient, infrahubSync, artifact, entry, known && exists, token)
+		if err != nil {
+			log.Error(err, "Failed to sync artifact", "artifact", artifact.ID)
+			errs = append(errs, fmt.Errorf("artifact %s: %w", artifact.ID, err))
+			if known {
+				syncedArtifacts = append(syncedArtifacts, entry)
 			}
+			continue
 		}
-		if !found {
-			contentReader, err := r.InfrahubClient.DownloadArtifact(
-				infrahubSync.Spec.Source.InfrahubAPIURL,
-				artifact.ID,
-				infrahubSync.Spec.Source.TargetBranch,
-				infrahubSync.Spec.Source.TargetDate,
-				token,
-			)
-			if err != nil {
-				return fmt.Errorf("failed to download artifact: %w", err)
-			}
-			var sb strings.Builder
-			if _, err := io.Copy(&sb, contentReader); err != nil {
-				return fmt.Errorf("failed to read artifact content: %w", err)
-			}
+		syncedArtifacts = append(syncedArtifacts, synced)
+	}
 
-			resource := &infrahubv1alpha1.VidraResource{
-				ObjectMeta: metav1.ObjectMeta{
-					Name: artifact.ID,
-					Finalizers: []string{
-						"vidraresource.infrahub.operators.com/finalizer",
-					},
-				},
-			}
+	return outcome, syncedArtifacts, utilerrors.Reduce(utilerrors.NewAggregate(errs))
+}
+
+// syncArtifact syncs the artifact to its VidraResource and returns the entry for the status. The artifact is only
+// downloaded if it was not synced before or its checksum differs from the previous entry.
+func (r *InfrahubSyncReconciler) syncArtifact(
+	ctx context.Context,
+	infrahubClient domain.InfrahubClient,
+	infrahubSync *infrahubv1alpha1.InfrahubSync,
+	artifact domain.Artifact,
+	entry infrahubv1alpha1.SyncedArtifact,
+	synced bool,
+	token domain.Token,
+) (infrahubv1alpha1.SyncedArtifact, error) {
+	// manifest stays nil for unchanged artifacts, their VidraResource only gets the current destination.
+	// Without a checksum a change cannot be detected, so the artifact is always downloaded.
+	var manifest *string
+	if !synced || artifact.Checksum == "" || entry.Checksum != artifact.Checksum {
+		content, err := r.downloadArtifact(ctx, infrahubClient, infrahubSync, artifact, token)
+		if err != nil {
+			return entry, err
+		}
+		manifest = &content
+		entry = infrahubv1alpha1.SyncedArtifact{
+			ArtifactID:        artifact.ID,
+			Checksum:          artifact.Checksum,
+			StorageID:         artifact.StorageID,
+			SyncedAt:          metav1.Now(),
+			VidraResourceName: artifact.ID,
+		}
+	}
+
+	opResult, err := r.syncVidraResource(ctx, infrahubSync, entry.VidraResourceName, manifest)
+	if err != nil {
+		return entry, err
+	}
+	log.FromContext(ctx).Info("Synced Infrahub to VidraResources", "name", entry.VidraResourceName, "operation", opResult, "downloaded", manifest != nil)
+	return entry, nil
+}
+
+// downloadArtifact downloads the content of the artifact and verifies it against the checksum reported by Infrahub
+func (r *InfrahubSyncReconciler) downloadArtifact(
+	ctx context.Context,
+	infrahubClient domain.InfrahubClient,
+	infrahubSync *infrahubv1alpha1.InfrahubSync,
+	artifact domain.Artifact,
+	token domain.Token,
+) (string, error) {
+	log := log.FromContext(ctx)
+
+	contentReader, err := infrahubClient.DownloadArtifact(
+		ctx,
+		infrahubSync.Spec.Source.InfrahubAPIURL,
+		artifact.ID,
+		infrahubSync.Spec.Source.TargetBranch,
+		infrahubSync.Spec.Source.TargetDate,
+		token,
+	)
+	if err != nil {
+		return "", NewConditionError(infrahubv1alpha1.ConditionArtifactsFetched, infrahubv1alpha1.ReasonDownloadFailed,
+			fmt.Errorf("failed to download artifact: %w", err))
+	}
+	if closer, ok := contentReader.(io.Closer); ok {
+		defer closer.Close() //nolint:errcheck
+	}
+
+	maxSize := r.MaxArtifactSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxArtifactSize
+	}
+	content, err := readArtifact(contentReader, maxSize)
+	if err != nil {
+		return "", err
+	}
+
+	// Truncated or altered content must not replace the manifest of the VidraResource
+	verified, err := verifyChecksum([]byte(content), artifact.Checksum)
+	if err != nil {
+		return "", NewConditionError(infrahubv1alpha1.ConditionArtifactsFetched, infrahubv1alpha1.ReasonChecksumMismatch, err)
+	}
+	if !verified {
+		log.Info("Skipping checksum verification of artifact, unknown checksum format", "artifact", artifact.ID, "checksum", artifact.Checksum)
+	}
+	return strings.TrimSpace(content), nil
+}
+
+// syncVidraResource creates or updates the VidraResource with the destination of the sync. The manifest is only
+// replaced if given, manifests above the inline limit are stored in chunk Secrets referenced by the VidraResource.
+func (r *InfrahubSyncReconciler) syncVidraResource(
+	ctx context.Context,
+	infrahubSync *infrahubv1alpha1.InfrahubSync,
+	name string,
+	manifest *string,
+) (controllerutil.OperationResult, error) {
+	resource := &infrahubv1alpha1.VidraResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Finalizers: []string{
+				"vidraresource.infrahub.operators.com/finalizer",
+			},
+		},
+	}
+
+	if err := ctrl.SetControllerReference(infrahubSync, resource, r.Scheme); err != nil {
+		return controllerutil.OperationResultNone, fmt.Errorf("failed to set controller reference: %w", err)
+	}
+
+	var chunks []string
+	var manifestFrom *infrahubv1alpha1.ManifestSource
+	var sha256 string
+	if manifest != nil && int64(len(*manifest)) > r.inlineManifestLimit() {
+		chunks = splitManifest(*manifest, manifestChunkSize)
+		sha256 = manifestSHA256(*manifest)
+		manifestFrom = &infrahubv1alpha1.ManifestSource{Namespace: r.manifestChunkNamespace(), SHA256: sha256}
+		for i := range chunks {
+			manifestFrom.Secrets = append(manifestFrom.Secrets, manifestChunkName(name, sha256, i))
+		}
 
-			if err := ctrl.SetControllerReference(infrahubSync, resource, r.Scheme); err != nil {
-				return fmt.Errorf("failed to set controller reference: %w", err)
+		// The chunks are complete before the VidraResource references them. A new VidraResource has no UID yet,
+		// its chunks get their owner once it was created.
+		owner := &infrahubv1alpha1.VidraResource{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, owner); err != nil {
+			if !errors.IsNotFound(err) {
+				return controllerutil.OperationResultNone, fmt.Errorf("failed to get VidraResource %s: %w", name, err)
 			}
+			owner.Name = name
+		}
+		if err := writeManifestChunks(ctx, r.Client, owner, r.manifestChunkNamespace(), sha256, chunks); err != nil {
+			return controllerutil.OperationResultNone, err
+		}
+	}
 
-			var opResult controllerutil.OperationResult
-			err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-				var innerErr error
-				opResult, innerErr = ctrl.CreateOrUpdate(ctx, r.Client, resource, func() error {
-					resource.Spec.Destination = infrahubv1alpha1.InfrahubSyncDestination{
-						Server:            infrahubSync.Spec.Destination.Server,
-						Namespace:         infrahubSync.Spec.Destination.Namespace,
-						ReconcileOnEvents: infrahubSync.Spec.Destination.ReconcileOnEvents,
-					}
-					resource.Spec.Manifest = strings.TrimSpace(sb.String())
-					return nil
-				})
-				return innerErr
-			})
-
-			if err != nil {
-				return fmt.Errorf("failed to create or update VidraResource %s: %w", resource.Name, err)
+	var opResult controllerutil.OperationResult
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		var innerErr error
+		opResult, innerErr = ctrl.CreateOrUpdate(ctx, r.Client, resource, func() error {
+			resource.Spec.Destination = infrahubv1alpha1.InfrahubSyncDestination{
+				Server:            infrahubSync.Spec.Destination.Server,
+				Namespace:         infrahubSync.Spec.Destination.Namespace,
+				ReconcileOnEvents: infrahubSync.Spec.Destination.ReconcileOnEvents,
+				ForceConflicts:    infrahubSync.Spec.Destination.ForceConflicts,
+				DriftPolicy:       infrahubSync.Spec.Destination.DriftPolicy,
+				IgnoreDifferences: infrahubSync.Spec.Destination.IgnoreDifferences,
+				PrunePolicy:       infrahubSync.Spec.Destination.PrunePolicy,
+				MaxPrunePercent:   infrahubSync.Spec.Destination.MaxPrunePercent,
+				Cluster:           infrahubSync.Spec.Destination.Cluster,
+				ClusterSelector:   infrahubSync.Spec.Destination.ClusterSelector,
 			}
+			if manifestFrom != nil {
+				resource.Spec.Manifest = ""
+				resource.Spec.ManifestFrom = manifestFrom
+			} else if manifest != nil {
+				resource.Spec.Manifest = *manifest
+				resource.Spec.ManifestFrom = nil
+			}
+			return nil
+		})
+		return innerErr
+	})
+	if err != nil {
+		return opResult, fmt.Errorf("failed to create or update VidraResource %s: %w", resource.Name, err)
+	}
 
-			log.Info("Synced Infrahub to VidraResources", "name", resource.Name, "operation", opResult)
+	// The chunks need the UID of the VidraResource as owner, the chunks of the previous manifest are removed now
+	// that the VidraResource no longer references them
+	if manifest != nil {
+		if err := storeManifestChunks(ctx, r.Client, resource, r.manifestChunkNamespace(), sha256, chunks); err != nil {
+			return opResult, err
 		}
 	}
+	return opResult, nil
+}
 
-	return nil
+// inlineManifestLimit returns the size up to which manifests are stored inline
+func (r *InfrahubSyncReconciler) inlineManifestLimit() int64 {
+	if r.InlineManifestLimit <= 0 {
+		return DefaultInlineManifestLimit
+	}
+	return r.InlineManifestLimit
+}
+
+// manifestChunkNamespace returns the namespace of the chunk Secrets of large manifests
+func (r *InfrahubSyncReconciler) manifestChunkNamespace() string {
+	if r.ManifestChunkNamespace == "" {
+		return DefaultManifestChunkNamespace
+	}
+	return r.ManifestChunkNamespace
 }
 
 func (r *InfrahubSyncReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	r.InfrahubClient = infrahub.NewClient()
 	// Create a direct (non-cached) client
 	cfg := mgr.GetConfig()
 	scheme := mgr.GetScheme()
@@ -291,11 +685,30 @@ func (r *InfrahubSyncReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if err := r.InitConfigWithClient(context.Background(), nonCachedClient, labelKey, labelValue); err != nil {
 		return fmt.Errorf("failed to initialize config: %w", err)
 	}
+	r.InfrahubClient = infrahub.NewClientWithOptions(r.ClientOptions)
 
-	return ctrl.NewControllerManagedBy(mgr).
+	r.retryLimiter = newRetryRateLimiter()
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&infrahubv1alpha1.InfrahubSync{},
-			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
-		Complete(r)
+			builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, syncRequestedPredicate()))).
+		WithOptions(controller.Options{RateLimiter: r.retryLimiter})
+	if r.WebhookEvents != nil {
+		b = b.WatchesRawSource(source.Channel(r.WebhookEvents, &handler.EnqueueRequestForObject{}))
+	}
+	return b.Complete(r)
+}
+
+// syncRequestedPredicate passes updates which change the SyncRequestedAnnotation, set by webhook receivers
+// of replicas which are not the leader
+func syncRequestedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld.GetAnnotations()[SyncRequestedAnnotation] != e.ObjectNew.GetAnnotations()[SyncRequestedAnnotation]
+		},
+	}
 }
 
 // InitConfigWithClient initializes the InfrahubSyncReconciler configuration
@@ -306,6 +719,10 @@ func (r *InfrahubSyncReconciler) InitConfigWithClient(ctx context.Context, k8sCl
 	// Start with the default values
 	r.RequeueAfter = defaultRequeue
 	r.QueryName = defaultQueryName
+	r.ClientOptions = infrahub.DefaultClientOptions()
+	r.MaxArtifactSize = DefaultMaxArtifactSize
+	r.InlineManifestLimit = DefaultInlineManifestLimit
+	r.ManifestChunkNamespace = DefaultManifestChunkNamespace
 	var configMaps v1.ConfigMapList
 	if err := k8s.GetSortedListByLabel(ctx, k8sClient, labelKey, labelValue, &configMaps); err != nil {
 		if strings.Contains(err.Error(), "no resources found with label") {
@@ -320,12 +737,15 @@ func (r *InfrahubSyncReconciler) InitConfigWithClient(ctx context.Context, k8sCl
 	}
 
 	var configMap *v1.ConfigMap
-	for _, cm := range configMaps.Items {
-		if okRequeue := cm.Data["requeueSyncAfter"] != ""; okRequeue || (cm.Data["queryName"] != "") {
-			configMap = &cm
+	for i := range configMaps.Items {
+		if hasConfigKey(configMaps.Items[i].Data) {
+			configMap = &configMaps.Items[i]
 			break
 		}
 	}
+	if configMap == nil {
+		return nil
+	}
 	// Check for 'requeueAfter' and update if available
 	requeueAfter, ok := configMap.Data["requeueSyncAfter"]
 	if ok {
@@ -344,5 +764,70 @@ func (r *InfrahubSyncReconciler) InitConfigWithClient(ctx context.Context, k8sCl
 		r.QueryName = queryName
 	}
 
+	// Check for the retries and timeouts of the requests to Infrahub
+	if value, ok := configMap.Data["infrahubMaxRetries"]; ok {
+		retries, err := strconv.Atoi(value)
+		if err != nil || retries < 0 {
+			return fmt.Errorf("invalid infrahubMaxRetries: %s", value)
+		}
+		r.ClientOptions.MaxRetries = retries
+	}
+	if value, ok := configMap.Data["infrahubRetryBackoff"]; ok {
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			return fmt.Errorf("invalid infrahubRetryBackoff: %s", value)
+		}
+		r.ClientOptions.RetryBackoff = duration
+	}
+	if value, ok := configMap.Data["infrahubRequestTimeout"]; ok {
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			return fmt.Errorf("invalid infrahubRequestTimeout: %s", value)
+		}
+		r.ClientOptions.RequestTimeout = duration
+	}
+
+	// Check for the rate and concurrency limits per Infrahub instance, 0 disables a limit
+	if value, ok := configMap.Data["infrahubRateLimit"]; ok {
+		limit, err := strconv.ParseFloat(value, 64)
+		if err != nil || limit < 0 {
+			return fmt.Errorf("invalid infrahubRateLimit: %s", value)
+		}
+		r.ClientOptions.RateLimit = limit
+	}
+	if value, ok := configMap.Data["infrahubRateBurst"]; ok {
+		burst, err := strconv.Atoi(value)
+		if err != nil || burst < 1 {
+			return fmt.Errorf("invalid infrahubRateBurst: %s", value)
+		}
+		r.ClientOptions.RateBurst = burst
+	}
+	if value, ok := configMap.Data["infrahubMaxInFlight"]; ok {
+		maxInFlight, err := strconv.Atoi(value)
+		if err != nil || maxInFlight < 0 {
+			return fmt.Errorf("invalid infrahubMaxInFlight: %s", value)
+		}
+		r.ClientOptions.MaxInFlight = maxInFlight
+	}
+
+	// Check for the size limits of artifacts and inline manifests, given as quantity (e.g. 10Mi)
+	if value, ok := configMap.Data["maxArtifactSize"]; ok {
+		size, err := resource.ParseQuantity(value)
+		if err != nil || size.Value() <= 0 {
+			return fmt.Errorf("invalid maxArtifactSize: %s", value)
+		}
+		r.MaxArtifactSize = size.Value()
+	}
+	if value, ok := configMap.Data["inlineManifestLimit"]; ok {
+		size, err := resource.ParseQuantity(value)
+		if err != nil || size.Value() <= 0 {
+			return fmt.Errorf("invalid inlineManifestLimit: %s", value)
+		}
+		r.InlineManifestLimit = size.Value()
+	}
+	if value, ok := configMap.Data["manifestChunkNamespace"]; ok && value != "" {
+		r.ManifestChunkNamespace = value
+	}
+
 	return nil
 }
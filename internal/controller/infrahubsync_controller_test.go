@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	goerrors "errors"
 	"fmt"
 	"strings"
 	"time"
 
 	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	"github.com/infrahub-operator/vidra/internal/adapter/infrahub"
 	"github.com/infrahub-operator/vidra/internal/domain"
 	mock "github.com/infrahub-operator/vidra/internal/mocks"
 
@@ -17,10 +19,14 @@ import (
 	"go.uber.org/mock/gomock"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrlruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -42,6 +48,7 @@ var _ = Describe("InfrahubSync Controller", func() {
 		targetDate        = "2025-01-01T00:00:00Z"
 		destinationServer = "https://kubernetes.default.svc"
 	)
+	mockToken := domain.Token{Value: "mock-token"}
 	artifact1 := &domain.Artifact{
 		ID:        "artifact-123",
 		StorageID: "storage-456",
@@ -56,6 +63,7 @@ var _ = Describe("InfrahubSync Controller", func() {
 	BeforeEach(func() {
 		mockCtrl = gomock.NewController(GinkgoT())
 		mockClient = mock.NewMockInfrahubClient(mockCtrl)
+		mockClient.EXPECT().WithConnection(domain.Connection{}).Return(mockClient, nil).AnyTimes()
 		ctx = context.Background()
 		namespacedName = types.NamespacedName{
 			Name: resourceName,
@@ -169,15 +177,15 @@ var _ = Describe("InfrahubSync Controller", func() {
 			It("should successfully reconcile the resource and call InfrahubClient methods if no artefacts are in infrahub", func() {
 				By("setting up mock expectations")
 				mockClient.EXPECT().
-					Login(apiURL, "test-user", "test-pass").
-					Return("mock-token", nil)
+					Login(gomock.Any(), apiURL, domain.Credentials{Username: "test-user", Password: "test-pass"}).
+					Return(mockToken, nil)
 
 				mockClient.EXPECT().
-					RunQuery("test-query", apiURL, artifactName, targetBranche, targetDate, "mock-token").
+					RunQuery(gomock.Any(), "test-query", apiURL, artifactName, targetBranche, targetDate, mockToken).
 					Return(&[]domain.Artifact{*artifact1}, nil)
 
 				mockClient.EXPECT().
-					DownloadArtifact(apiURL, artifact1.ID, targetBranche, targetDate, "mock-token").
+					DownloadArtifact(gomock.Any(), apiURL, artifact1.ID, targetBranche, targetDate, mockToken).
 					Return(bytes.NewReader([]byte(`{
 							"apiVersion": "v1", 
 							"kind": "ConfigMap", 
@@ -218,15 +226,15 @@ var _ = Describe("InfrahubSync Controller", func() {
 			It("should creat the vidraResource if the artifact id is present", func() {
 				By("setting up mock expectations")
 				mockClient.EXPECT().
-					Login(apiURL, "test-user", "test-pass").
-					Return("mock-token", nil)
+					Login(gomock.Any(), apiURL, domain.Credentials{Username: "test-user", Password: "test-pass"}).
+					Return(mockToken, nil)
 
 				mockClient.EXPECT().
-					RunQuery("test-query", apiURL, artifactName, targetBranche, targetDate, "mock-token").
+					RunQuery(gomock.Any(), "test-query", apiURL, artifactName, targetBranche, targetDate, mockToken).
 					Return(&[]domain.Artifact{*artifact1}, nil)
 
 				mockClient.EXPECT().
-					DownloadArtifact(apiURL, artifact1.ID, targetBranche, targetDate, "mock-token").
+					DownloadArtifact(gomock.Any(), apiURL, artifact1.ID, targetBranche, targetDate, mockToken).
 					Return(bytes.NewReader([]byte(`{
 							"apiVersion": "v1", 
 							"kind": "ConfigMap", 
@@ -258,25 +266,177 @@ var _ = Describe("InfrahubSync Controller", func() {
 				}, infrahubSync)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(infrahubSync.Status.SyncState).To(Equal(infrahubv1alpha1.StateSucceeded))
+				Expect(meta.IsStatusConditionTrue(infrahubSync.Status.Conditions, infrahubv1alpha1.ConditionReady)).To(BeTrue())
+				Expect(meta.IsStatusConditionTrue(infrahubSync.Status.Conditions, infrahubv1alpha1.ConditionArtifactsFetched)).To(BeTrue())
+				Expect(infrahubSync.Status.ObservedGeneration).To(Equal(infrahubSync.Generation))
+			})
+
+			It("should create one vidraResource per artifact matched by the selector", func() {
+				instance := &infrahubv1alpha1.InfrahubSync{}
+				Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+				instance.Spec.Source.ArtifactName = ""
+				instance.Spec.Source.Selector = &infrahubv1alpha1.ArtifactSelector{
+					ArtifactDefinition: "leaf_config",
+					TargetGroup:        "leafs",
+					NameGlob:           "leaf-*",
+				}
+				Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+
+				mockClient.EXPECT().
+					Login(gomock.Any(), apiURL, gomock.Any()).
+					Return(mockToken, nil)
+
+				leaf1, leaf2, spine := *artifact1, *artifact2, domain.Artifact{ID: "artifact-spine", Name: "spine-01"}
+				leaf1.Name, leaf2.Name = "leaf-01", "leaf-02"
+				mockClient.EXPECT().
+					QueryArtifacts(gomock.Any(), apiURL, domain.ArtifactQuery{
+						Branch:      targetBranche,
+						At:          targetDate,
+						Definition:  "leaf_config",
+						TargetGroup: "leafs",
+					}, mockToken).
+					Return(&[]domain.Artifact{leaf1, leaf2, spine}, nil)
+
+				for _, artifact := range []domain.Artifact{leaf1, leaf2} {
+					mockClient.EXPECT().
+						DownloadArtifact(gomock.Any(), apiURL, artifact.ID, targetBranche, targetDate, mockToken).
+						Return(bytes.NewReader([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "example"}}`)), nil)
+				}
+
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: namespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				for _, artifact := range []domain.Artifact{leaf1, leaf2} {
+					Expect(k8sClient.Get(ctx, types.NamespacedName{Name: artifact.ID, Namespace: namespace}, &infrahubv1alpha1.VidraResource{})).To(Succeed())
+				}
+				err = k8sClient.Get(ctx, types.NamespacedName{Name: spine.ID, Namespace: namespace}, &infrahubv1alpha1.VidraResource{})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			})
+
+			It("should record the synced artifacts and skip downloading unchanged artifacts", func() {
+				expectQuery := func(artifacts ...domain.Artifact) {
+					mockClient.EXPECT().
+						Login(gomock.Any(), apiURL, gomock.Any()).
+						Return(mockToken, nil)
+					mockClient.EXPECT().
+						RunQuery(gomock.Any(), "test-query", apiURL, artifactName, targetBranche, targetDate, mockToken).
+						Return(&artifacts, nil)
+				}
+				expectDownload := func(artifact domain.Artifact) {
+					mockClient.EXPECT().
+						DownloadArtifact(gomock.Any(), apiURL, artifact.ID, targetBranche, targetDate, mockToken).
+						Return(bytes.NewReader([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "example"}}`)), nil)
+				}
+				syncedArtifacts := func() []infrahubv1alpha1.SyncedArtifact {
+					instance := &infrahubv1alpha1.InfrahubSync{}
+					Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+					return instance.Status.Artifacts
+				}
+
+				By("downloading both artifacts on the first sync")
+				expectQuery(*artifact1, *artifact2)
+				expectDownload(*artifact1)
+				expectDownload(*artifact2)
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				synced := syncedArtifacts()
+				Expect(synced).To(HaveLen(2))
+				Expect(synced[0].ArtifactID).To(Equal(artifact1.ID))
+				Expect(synced[0].Checksum).To(Equal(artifact1.Checksum))
+				Expect(synced[0].StorageID).To(Equal(artifact1.StorageID))
+				Expect(synced[0].VidraResourceName).To(Equal(artifact1.ID))
+				Expect(synced[0].SyncedAt.IsZero()).To(BeFalse())
+
+				By("downloading only the changed artifact and dropping the removed one")
+				changed := *artifact1
+				changed.Checksum = "checksum-changed"
+				expectQuery(changed)
+				expectDownload(changed)
+				_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				synced = syncedArtifacts()
+				Expect(synced).To(HaveLen(1))
+				Expect(synced[0].Checksum).To(Equal("checksum-changed"))
+
+				By("not downloading an unchanged artifact again")
+				expectQuery(changed)
+				_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(syncedArtifacts()).To(Equal(synced))
+			})
+
+			It("should skip querying Infrahub and suspend its VidraResources while suspended", func() {
+				expectSync := func(download bool) {
+					mockClient.EXPECT().
+						Login(gomock.Any(), apiURL, domain.Credentials{Username: "test-user", Password: "test-pass"}).
+						Return(mockToken, nil)
+					mockClient.EXPECT().
+						RunQuery(gomock.Any(), "test-query", apiURL, artifactName, targetBranche, targetDate, mockToken).
+						Return(&[]domain.Artifact{*artifact1}, nil)
+					if download {
+						mockClient.EXPECT().
+							DownloadArtifact(gomock.Any(), apiURL, artifact1.ID, targetBranche, targetDate, mockToken).
+							Return(bytes.NewReader([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "example"}}`)), nil)
+					}
+				}
+				setSuspend := func(suspend bool) {
+					instance := &infrahubv1alpha1.InfrahubSync{}
+					Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+					instance.Spec.Suspend = suspend
+					Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+				}
+
+				By("reconciling the resource")
+				expectSync(true)
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("reconciling the suspended resource without calling Infrahub")
+				setSuspend(true)
+				result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{}))
+
+				instance := &infrahubv1alpha1.InfrahubSync{}
+				Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+				Expect(meta.IsStatusConditionTrue(instance.Status.Conditions, infrahubv1alpha1.ConditionSuspended)).To(BeTrue())
+				vidraResource := &infrahubv1alpha1.VidraResource{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: artifact1.ID}, vidraResource)).To(Succeed())
+				Expect(vidraResource.Spec.Suspend).To(BeTrue())
+
+				By("resuming the resource, the unchanged artifact is not downloaded again")
+				setSuspend(false)
+				expectSync(false)
+				_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+				suspended := meta.FindStatusCondition(instance.Status.Conditions, infrahubv1alpha1.ConditionSuspended)
+				Expect(suspended).NotTo(BeNil())
+				Expect(suspended.Status).To(Equal(metav1.ConditionFalse))
+				Expect(suspended.Reason).To(Equal(infrahubv1alpha1.ReasonResumed))
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: artifact1.ID}, vidraResource)).To(Succeed())
+				Expect(vidraResource.Spec.Suspend).To(BeFalse())
 			})
 
 			It("should delete the vidraResource if the artifact id is not present", func() {
 				By("setting up mock expectations")
 				mockClient.EXPECT().
-					Login(apiURL, "test-user", "test-pass").
-					Return("mock-token", nil)
+					Login(gomock.Any(), apiURL, domain.Credentials{Username: "test-user", Password: "test-pass"}).
+					Return(mockToken, nil)
 				mockClient.EXPECT().
-					RunQuery("test-query", apiURL, artifactName, targetBranche, targetDate, "mock-token").
+					RunQuery(gomock.Any(), "test-query", apiURL, artifactName, targetBranche, targetDate, mockToken).
 					Return(&[]domain.Artifact{*artifact1, *artifact2}, nil)
 				mockClient.EXPECT().
-					DownloadArtifact(apiURL, gomock.Any(), targetBranche, targetDate, "mock-token").
+					DownloadArtifact(gomock.Any(), apiURL, gomock.Any(), targetBranche, targetDate, mockToken).
 					Return(bytes.NewReader([]byte(`{
 							"apiVersion": "v1", 
 							"kind": "ConfigMap", 
 							"metadata": {
 								"name": "example"
 							}
-						}`)), nil).Times(3)
+						}`)), nil).Times(2)
 
 				By("reconciling the resource with two artifacts")
 				_, err := reconciler.Reconcile(ctx, reconcile.Request{
@@ -300,10 +460,10 @@ var _ = Describe("InfrahubSync Controller", func() {
 				Expect(vidraResource.Name).To(Equal(artifact2.ID))
 
 				mockClient.EXPECT().
-					Login(apiURL, "test-user", "test-pass").
-					Return("mock-token", nil)
+					Login(gomock.Any(), apiURL, domain.Credentials{Username: "test-user", Password: "test-pass"}).
+					Return(mockToken, nil)
 				mockClient.EXPECT().
-					RunQuery("test-query", apiURL, artifactName, targetBranche, targetDate, "mock-token").
+					RunQuery(gomock.Any(), "test-query", apiURL, artifactName, targetBranche, targetDate, mockToken).
 					Return(&[]domain.Artifact{*artifact2}, nil)
 
 				By("reconciling the resource with one artifact")
@@ -366,15 +526,15 @@ var _ = Describe("InfrahubSync Controller", func() {
 
 				By("setting up mock expectations")
 				mockClient.EXPECT().
-					Login(apiURL, "test-user2", "test-pass2").
-					Return("mock-token", nil)
+					Login(gomock.Any(), apiURL, domain.Credentials{Username: "test-user2", Password: "test-pass2"}).
+					Return(mockToken, nil)
 
 				mockClient.EXPECT().
-					RunQuery("test-query", apiURL, artifactName, targetBranche, targetDate, "mock-token").
+					RunQuery(gomock.Any(), "test-query", apiURL, artifactName, targetBranche, targetDate, mockToken).
 					Return(&[]domain.Artifact{*artifact1}, nil)
 
 				mockClient.EXPECT().
-					DownloadArtifact(apiURL, artifact1.ID, targetBranche, targetDate, "mock-token").
+					DownloadArtifact(gomock.Any(), apiURL, artifact1.ID, targetBranche, targetDate, mockToken).
 					Return(bytes.NewReader([]byte(`{
 							"apiVersion": "v1", 
 							"kind": "ConfigMap", 
@@ -393,13 +553,13 @@ var _ = Describe("InfrahubSync Controller", func() {
 			It("should update the vidraResource if the artifact checksum is changed", func() {
 				By("setting up mock expectations")
 				mockClient.EXPECT().
-					Login(apiURL, "test-user", "test-pass").
-					Return("mock-token", nil)
+					Login(gomock.Any(), apiURL, domain.Credentials{Username: "test-user", Password: "test-pass"}).
+					Return(mockToken, nil)
 				mockClient.EXPECT().
-					RunQuery("test-query", apiURL, artifactName, targetBranche, targetDate, "mock-token").
+					RunQuery(gomock.Any(), "test-query", apiURL, artifactName, targetBranche, targetDate, mockToken).
 					Return(&[]domain.Artifact{*artifact1}, nil)
 				mockClient.EXPECT().
-					DownloadArtifact(apiURL, artifact1.ID, targetBranche, targetDate, "mock-token").
+					DownloadArtifact(gomock.Any(), apiURL, artifact1.ID, targetBranche, targetDate, mockToken).
 					Return(bytes.NewReader([]byte(`{
 							"apiVersion": "v1", 
 							"kind": "ConfigMap", 
@@ -424,18 +584,18 @@ var _ = Describe("InfrahubSync Controller", func() {
 
 				By("updating the vidraResource with new checksum and storage id")
 				mockClient.EXPECT().
-					Login(apiURL, "test-user", "test-pass").
-					Return("mock-token", nil)
+					Login(gomock.Any(), apiURL, domain.Credentials{Username: "test-user", Password: "test-pass"}).
+					Return(mockToken, nil)
 
 				artifact1Updated := *artifact1
 				artifact1Updated.Checksum = "new-checksum-123"
 				artifact1Updated.StorageID = "new-storage-456"
 
 				mockClient.EXPECT().
-					RunQuery("test-query", apiURL, artifactName, targetBranche, targetDate, "mock-token").
+					RunQuery(gomock.Any(), "test-query", apiURL, artifactName, targetBranche, targetDate, mockToken).
 					Return(&[]domain.Artifact{artifact1Updated}, nil)
 				mockClient.EXPECT().
-					DownloadArtifact(apiURL, artifact1.ID, targetBranche, targetDate, "mock-token").
+					DownloadArtifact(gomock.Any(), apiURL, artifact1.ID, targetBranche, targetDate, mockToken).
 					Return(bytes.NewReader([]byte(`{
 							"apiVersion": "v1", 
 							"kind": "ConfigMap", 
@@ -464,13 +624,13 @@ var _ = Describe("InfrahubSync Controller", func() {
 			It("should return an error when resource creation or update fails", func() {
 				By("setting up mock expectations")
 				mockClient.EXPECT().
-					Login(apiURL, "test-user", "test-pass").
-					Return("mock-token", nil)
+					Login(gomock.Any(), apiURL, domain.Credentials{Username: "test-user", Password: "test-pass"}).
+					Return(mockToken, nil)
 				mockClient.EXPECT().
-					RunQuery("test-query", apiURL, artifactName, targetBranche, targetDate, "mock-token").
+					RunQuery(gomock.Any(), "test-query", apiURL, artifactName, targetBranche, targetDate, mockToken).
 					Return(&[]domain.Artifact{*artifact1}, nil)
 				mockClient.EXPECT().
-					DownloadArtifact(apiURL, artifact1.ID, targetBranche, targetDate, "mock-token").
+					DownloadArtifact(gomock.Any(), apiURL, artifact1.ID, targetBranche, targetDate, mockToken).
 					Return(bytes.NewReader([]byte(`{}`)), nil)
 
 				By("reconciling the resource with failing client (Update)")
@@ -489,7 +649,7 @@ var _ = Describe("InfrahubSync Controller", func() {
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("failed to create or update VidraResource artifact-123: simulated failure: Update"))
 
-				Expect(result).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: defaultRetryBackoff}))
 				if failingClient, ok := failingK8sClient.(*mock.FailingUpdateClient); ok {
 					failingClient.FailingMethod = ""
 				}
@@ -498,16 +658,16 @@ var _ = Describe("InfrahubSync Controller", func() {
 			It("should return an error when resource deletion fails", func() {
 				By("setting up mock expectations")
 				mockClient.EXPECT().
-					Login(apiURL, "test-user", "test-pass").
-					Return("mock-token", nil)
+					Login(gomock.Any(), apiURL, domain.Credentials{Username: "test-user", Password: "test-pass"}).
+					Return(mockToken, nil)
 				mockClient.EXPECT().
-					RunQuery("test-query", apiURL, artifactName, targetBranche, targetDate, "mock-token").
+					RunQuery(gomock.Any(), "test-query", apiURL, artifactName, targetBranche, targetDate, mockToken).
 					Return(&[]domain.Artifact{*artifact1, *artifact2}, nil)
 				mockClient.EXPECT().
-					DownloadArtifact(apiURL, artifact1.ID, targetBranche, targetDate, "mock-token").
+					DownloadArtifact(gomock.Any(), apiURL, artifact1.ID, targetBranche, targetDate, mockToken).
 					Return(bytes.NewReader([]byte(`{}`)), nil)
 				mockClient.EXPECT().
-					DownloadArtifact(apiURL, artifact2.ID, targetBranche, targetDate, "mock-token").
+					DownloadArtifact(gomock.Any(), apiURL, artifact2.ID, targetBranche, targetDate, mockToken).
 					Return(bytes.NewReader([]byte(`{}`)), nil)
 				By("reconciling the resource with failing client ()")
 				reconciler := &InfrahubSyncReconciler{
@@ -521,10 +681,10 @@ var _ = Describe("InfrahubSync Controller", func() {
 				Expect(err).NotTo(HaveOccurred())
 
 				mockClient.EXPECT().
-					Login(apiURL, "test-user", "test-pass").
-					Return("mock-token", nil)
+					Login(gomock.Any(), apiURL, domain.Credentials{Username: "test-user", Password: "test-pass"}).
+					Return(mockToken, nil)
 				mockClient.EXPECT().
-					RunQuery("test-query", apiURL, artifactName, targetBranche, targetDate, "mock-token").
+					RunQuery(gomock.Any(), "test-query", apiURL, artifactName, targetBranche, targetDate, mockToken).
 					Return(&[]domain.Artifact{*artifact1}, nil)
 
 				By("reconciling the resource with failing client (Delete)")
@@ -536,7 +696,7 @@ var _ = Describe("InfrahubSync Controller", func() {
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("failed to delete stale VidraResource artifact-456: simulated failure: Delete"))
 
-				Expect(result).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: defaultRetryBackoff}))
 				if failingClient, ok := failingK8sClient.(*mock.FailingUpdateClient); ok {
 					failingClient.FailingMethod = ""
 				}
@@ -559,7 +719,7 @@ var _ = Describe("InfrahubSync Controller", func() {
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("no secret found with InfrahubAPIURL: %s, error: failed to list resources: simulated failure: List - [map[infrahub-api-url:", apiURL)))
 
-				Expect(result).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: defaultRetryBackoff}))
 				if failingClient, ok := failingK8sClient.(*mock.FailingUpdateClient); ok {
 					failingClient.FailingMethod = ""
 				}
@@ -567,8 +727,8 @@ var _ = Describe("InfrahubSync Controller", func() {
 
 			It("should return error if login fails", func() {
 				mockClient.EXPECT().
-					Login(apiURL, gomock.Any(), gomock.Any()).
-					Return("", fmt.Errorf("login failed"))
+					Login(gomock.Any(), apiURL, gomock.Any()).
+					Return(domain.Token{}, fmt.Errorf("login failed"))
 
 				_, err := reconciler.Reconcile(ctx, reconcile.Request{
 					NamespacedName: namespacedName,
@@ -577,13 +737,51 @@ var _ = Describe("InfrahubSync Controller", func() {
 				Expect(err.Error()).To(ContainSubstring("login failed"))
 			})
 
+			It("should back off exponentially per object after failed logins and reset after a success", func() {
+				instance := &infrahubv1alpha1.InfrahubSync{}
+				Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+				instance.Spec.SyncInterval = &metav1.Duration{Duration: 5 * time.Minute}
+				instance.Spec.Retry = &infrahubv1alpha1.RetryStrategy{
+					Limit:      3,
+					Backoff:    &metav1.Duration{Duration: 20 * time.Second},
+					MaxBackoff: &metav1.Duration{Duration: 30 * time.Second},
+				}
+				Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+
+				mockClient.EXPECT().
+					Login(gomock.Any(), apiURL, gomock.Any()).
+					Return(domain.Token{}, fmt.Errorf("login failed")).Times(4)
+				for _, expected := range []time.Duration{20 * time.Second, 30 * time.Second, 30 * time.Second, 5 * time.Minute} {
+					result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+					Expect(err).To(HaveOccurred())
+					Expect(result).To(Equal(reconcile.Result{RequeueAfter: expected}))
+				}
+
+				Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+				Expect(instance.Status.FailureCount).To(Equal(int32(4)))
+				Expect(instance.Status.NextSyncTime.Time).To(BeTemporally("~", time.Now().Add(5*time.Minute), 5*time.Second))
+
+				mockClient.EXPECT().
+					Login(gomock.Any(), apiURL, domain.Credentials{Username: "test-user", Password: "test-pass"}).
+					Return(mockToken, nil)
+				mockClient.EXPECT().
+					RunQuery(gomock.Any(), "test-query", apiURL, artifactName, targetBranche, targetDate, mockToken).
+					Return(&[]domain.Artifact{}, nil)
+				result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal(reconcile.Result{RequeueAfter: 5 * time.Minute}))
+
+				Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+				Expect(instance.Status.FailureCount).To(BeZero())
+			})
+
 			It("should return error if query fails", func() {
 				mockClient.EXPECT().
-					Login(apiURL, gomock.Any(), gomock.Any()).
-					Return("mock-token", nil)
+					Login(gomock.Any(), apiURL, gomock.Any()).
+					Return(mockToken, nil)
 
 				mockClient.EXPECT().
-					RunQuery("test-query", apiURL, artifactName, targetBranche, targetDate, "mock-token").
+					RunQuery(gomock.Any(), "test-query", apiURL, artifactName, targetBranche, targetDate, mockToken).
 					Return(nil, fmt.Errorf("query failed"))
 
 				_, err := reconciler.Reconcile(ctx, reconcile.Request{
@@ -593,6 +791,41 @@ var _ = Describe("InfrahubSync Controller", func() {
 				Expect(err.Error()).To(ContainSubstring("query failed"))
 			})
 
+			It("should query the artifacts with GraphQL and the filters of the sync in GraphQL mode", func() {
+				instance := &infrahubv1alpha1.InfrahubSync{}
+				Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+				instance.Spec.Source.Query = &infrahubv1alpha1.ArtifactQuery{
+					Mode:       infrahubv1alpha1.QueryModeGraphQL,
+					ObjectIDs:  []string{"device-1"},
+					Statuses:   []string{"Ready"},
+					Definition: "webserver",
+					PageSize:   50,
+				}
+				Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+
+				mockClient.EXPECT().
+					Login(gomock.Any(), apiURL, gomock.Any()).
+					Return(mockToken, nil)
+
+				mockClient.EXPECT().
+					QueryArtifacts(gomock.Any(), apiURL, domain.ArtifactQuery{
+						Name:       artifactName,
+						Branch:     targetBranche,
+						At:         targetDate,
+						ObjectIDs:  []string{"device-1"},
+						Statuses:   []string{"Ready"},
+						Definition: "webserver",
+						PageSize:   50,
+					}, mockToken).
+					Return(nil, fmt.Errorf("graphql query failed"))
+
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: namespacedName,
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("graphql query failed"))
+			})
+
 			It("should return error if the secret is invalid", func() {
 				By("creating the secret with invalid credentials")
 				secret := &v1.Secret{}
@@ -623,8 +856,8 @@ var _ = Describe("InfrahubSync Controller", func() {
 				}()
 
 				mockClient.EXPECT().
-					Login(apiURL, gomock.Any(), gomock.Any()).
-					Return("", fmt.Errorf("invalid secret"))
+					Login(gomock.Any(), apiURL, gomock.Any()).
+					Return(domain.Token{}, fmt.Errorf("invalid secret"))
 
 				By("reconciling the resource with invalid secret")
 				_, err := reconciler.Reconcile(ctx, reconcile.Request{
@@ -668,7 +901,77 @@ var _ = Describe("InfrahubSync Controller", func() {
 				})
 
 				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(ContainSubstring("no secret found with both username and password fields"))
+				Expect(err.Error()).To(ContainSubstring("no secret found with a token or both username and password fields"))
+			})
+
+			It("should prefer an API token over username and password", func() {
+				By("adding a token to the secret")
+				secret := &v1.Secret{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{
+					Name:      "infrahub-credentials",
+					Namespace: namespace,
+				}, secret)).To(Succeed())
+				secret.Data["token"] = []byte("api-token\n")
+				Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+
+				mockClient.EXPECT().
+					Login(gomock.Any(), apiURL, domain.Credentials{APIToken: "api-token"}).
+					Return(domain.Token{}, fmt.Errorf("invalid API token"))
+
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: namespacedName,
+				})
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("invalid API token"))
+			})
+
+			It("should connect with the CA bundle, client certificate and proxy of the sync", func() {
+				instance := &infrahubv1alpha1.InfrahubSync{}
+				Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+				instance.Spec.Source.ProxyURL = "http://proxy.example.com:3128"
+				instance.Spec.Source.TLS = &infrahubv1alpha1.InfrahubTLSConfig{
+					CABundle:         &infrahubv1alpha1.CABundleSource{Kind: "ConfigMap", Name: "infrahub-ca", Namespace: namespace, Key: "ca.crt"},
+					ClientCertSecret: &infrahubv1alpha1.SecretReference{Name: "infrahub-client-cert", Namespace: namespace},
+				}
+				Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+
+				By("reconciling without the CA bundle ConfigMap")
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("failed to get CA bundle ConfigMap"))
+				Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+				reachable := meta.FindStatusCondition(instance.Status.Conditions, infrahubv1alpha1.ConditionInfrahubReachable)
+				Expect(reachable).NotTo(BeNil())
+				Expect(reachable.Reason).To(Equal(infrahubv1alpha1.ReasonInvalidConnection))
+
+				By("creating the CA bundle and the client certificate")
+				caBundle := &v1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "infrahub-ca", Namespace: namespace},
+					Data:       map[string]string{"ca.crt": "ca-pem"},
+				}
+				Expect(k8sClient.Create(ctx, caBundle)).To(Succeed())
+				defer func() { Expect(k8sClient.Delete(ctx, caBundle)).To(Succeed()) }()
+				clientCert := &v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "infrahub-client-cert", Namespace: namespace},
+					Type:       v1.SecretTypeTLS,
+					Data:       map[string][]byte{v1.TLSCertKey: []byte("cert-pem"), v1.TLSPrivateKeyKey: []byte("key-pem")},
+				}
+				Expect(k8sClient.Create(ctx, clientCert)).To(Succeed())
+				defer func() { Expect(k8sClient.Delete(ctx, clientCert)).To(Succeed()) }()
+
+				mockClient.EXPECT().
+					WithConnection(domain.Connection{
+						CABundle:   []byte("ca-pem"),
+						ClientCert: []byte("cert-pem"),
+						ClientKey:  []byte("key-pem"),
+						ProxyURL:   "http://proxy.example.com:3128",
+					}).
+					Return(nil, fmt.Errorf("invalid client certificate"))
+
+				_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("invalid client certificate"))
 			})
 
 			It("should return error if the secret is empty", func() {
@@ -697,8 +1000,8 @@ var _ = Describe("InfrahubSync Controller", func() {
 				Expect(k8sClient.Create(ctx, emptySecret)).To(Succeed())
 
 				mockClient.EXPECT().
-					Login(apiURL, "", "").
-					Return("", fmt.Errorf("missing username, password in the secret"))
+					Login(gomock.Any(), apiURL, domain.Credentials{}).
+					Return(domain.Token{}, fmt.Errorf("missing username, password in the secret"))
 
 				By("reconciling the resource with empty secret")
 				_, err := reconciler.Reconcile(ctx, reconcile.Request{
@@ -712,13 +1015,13 @@ var _ = Describe("InfrahubSync Controller", func() {
 			It("should return error if DownloadArtifact fails", func() {
 				By("setting up the mock client to return an error and reconcile")
 				mockClient.EXPECT().
-					Login(apiURL, "test-user", "test-pass").
-					Return("mock-token", nil)
+					Login(gomock.Any(), apiURL, domain.Credentials{Username: "test-user", Password: "test-pass"}).
+					Return(mockToken, nil)
 				mockClient.EXPECT().
-					RunQuery("test-query", apiURL, artifactName, targetBranche, targetDate, "mock-token").
+					RunQuery(gomock.Any(), "test-query", apiURL, artifactName, targetBranche, targetDate, mockToken).
 					Return(&[]domain.Artifact{*artifact1}, nil)
 				mockClient.EXPECT().
-					DownloadArtifact(apiURL, artifact1.ID, targetBranche, targetDate, "mock-token").
+					DownloadArtifact(gomock.Any(), apiURL, artifact1.ID, targetBranche, targetDate, mockToken).
 					Return(nil, fmt.Errorf("download error"))
 
 				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
@@ -730,7 +1033,91 @@ var _ = Describe("InfrahubSync Controller", func() {
 				err = k8sClient.Get(ctx, namespacedName, instance)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(instance.Status.SyncState).To(Equal(infrahubv1alpha1.StateFailed))
-				Expect(instance.Status.LastError).To(Equal("failed to download artifact: download error"))
+				Expect(instance.Status.LastError).To(Equal(fmt.Sprintf("artifact %s: failed to download artifact: download error", artifact1.ID)))
+
+				ready := meta.FindStatusCondition(instance.Status.Conditions, infrahubv1alpha1.ConditionReady)
+				Expect(ready).NotTo(BeNil())
+				Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+				Expect(ready.Reason).To(Equal(infrahubv1alpha1.ReasonDownloadFailed))
+				fetched := meta.FindStatusCondition(instance.Status.Conditions, infrahubv1alpha1.ConditionArtifactsFetched)
+				Expect(fetched).NotTo(BeNil())
+				Expect(fetched.Status).To(Equal(metav1.ConditionFalse))
+			})
+
+			It("should refuse to update the vidraResource if the content does not match the checksum", func() {
+				corrupted := *artifact1
+				corrupted.Checksum = "09dbf6ade1b4036ab4f95f9fe03771a6"
+				mockClient.EXPECT().
+					Login(gomock.Any(), apiURL, gomock.Any()).
+					Return(mockToken, nil)
+				mockClient.EXPECT().
+					RunQuery(gomock.Any(), "test-query", apiURL, artifactName, targetBranche, targetDate, mockToken).
+					Return(&[]domain.Artifact{corrupted}, nil)
+				mockClient.EXPECT().
+					DownloadArtifact(gomock.Any(), apiURL, corrupted.ID, targetBranche, targetDate, mockToken).
+					Return(bytes.NewReader([]byte("apiVersion: v1\nkind: Conf")), nil)
+
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("checksum mismatch"))
+
+				err = k8sClient.Get(ctx, types.NamespacedName{Name: corrupted.ID, Namespace: namespace}, &infrahubv1alpha1.VidraResource{})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+
+				instance := &infrahubv1alpha1.InfrahubSync{}
+				Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+				Expect(instance.Status.SyncState).To(Equal(infrahubv1alpha1.StateFailed))
+				fetched := meta.FindStatusCondition(instance.Status.Conditions, infrahubv1alpha1.ConditionArtifactsFetched)
+				Expect(fetched).NotTo(BeNil())
+				Expect(fetched.Reason).To(Equal(infrahubv1alpha1.ReasonChecksumMismatch))
+			})
+
+			It("should fail with a clear error if the artifact exceeds the maximum size", func() {
+				reconciler.MaxArtifactSize = 16
+				mockClient.EXPECT().
+					Login(gomock.Any(), apiURL, gomock.Any()).
+					Return(mockToken, nil)
+				mockClient.EXPECT().
+					RunQuery(gomock.Any(), "test-query", apiURL, artifactName, targetBranche, targetDate, mockToken).
+					Return(&[]domain.Artifact{*artifact1}, nil)
+				mockClient.EXPECT().
+					DownloadArtifact(gomock.Any(), apiURL, artifact1.ID, targetBranche, targetDate, mockToken).
+					Return(bytes.NewReader([]byte(`{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "example"}}`)), nil)
+
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+				Expect(err).To(MatchError(ContainSubstring("exceeds the maximum artifact size of 16 bytes")))
+
+				instance := &infrahubv1alpha1.InfrahubSync{}
+				Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+				fetched := meta.FindStatusCondition(instance.Status.Conditions, infrahubv1alpha1.ConditionArtifactsFetched)
+				Expect(fetched).NotTo(BeNil())
+				Expect(fetched.Reason).To(Equal(infrahubv1alpha1.ReasonArtifactTooLarge))
+			})
+
+			It("should store manifests above the inline limit in chunk Secrets", func() {
+				reconciler.InlineManifestLimit = 16
+				reconciler.ManifestChunkNamespace = "default"
+				manifest := `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "example"}}`
+				mockClient.EXPECT().
+					Login(gomock.Any(), apiURL, gomock.Any()).
+					Return(mockToken, nil)
+				mockClient.EXPECT().
+					RunQuery(gomock.Any(), "test-query", apiURL, artifactName, targetBranche, targetDate, mockToken).
+					Return(&[]domain.Artifact{*artifact1}, nil)
+				mockClient.EXPECT().
+					DownloadArtifact(gomock.Any(), apiURL, artifact1.ID, targetBranche, targetDate, mockToken).
+					Return(bytes.NewReader([]byte(manifest)), nil)
+
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+				Expect(err).NotTo(HaveOccurred())
+
+				vidraResource := &infrahubv1alpha1.VidraResource{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: artifact1.ID}, vidraResource)).To(Succeed())
+				Expect(vidraResource.Spec.Manifest).To(BeEmpty())
+				Expect(vidraResource.Spec.ManifestFrom).NotTo(BeNil())
+				Expect(vidraResource.Spec.ManifestFrom.Namespace).To(Equal("default"))
+				Expect(vidraResource.Spec.ManifestFrom.Secrets).To(HaveLen(1))
+				Expect(loadManifest(ctx, k8sClient, vidraResource)).To(Equal(manifest))
 			})
 
 			It("should ignore the resource if it is not found", func() {
@@ -760,6 +1147,47 @@ var _ = Describe("InfrahubSync Controller", func() {
 		})
 	})
 })
+var _ = Describe("Processing artifacts", func() {
+	It("should sync the other artifacts and keep the entry of a failed artifact", func() {
+		ctx := context.Background()
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(infrahubv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		previous := infrahubv1alpha1.SyncedArtifact{ArtifactID: "broken", Checksum: "old", VidraResourceName: "broken"}
+		infrahubSync := &infrahubv1alpha1.InfrahubSync{
+			ObjectMeta: metav1.ObjectMeta{Name: "sync", UID: "sync-uid"},
+			Spec: infrahubv1alpha1.InfrahubSyncSpec{
+				Source: infrahubv1alpha1.InfrahubSyncSource{InfrahubAPIURL: "https://infrahub.example.com"},
+			},
+			Status: infrahubv1alpha1.InfrahubSyncStatus{Artifacts: []infrahubv1alpha1.SyncedArtifact{previous}},
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(infrahubSync).Build()
+
+		mockClient := mock.NewMockInfrahubClient(gomock.NewController(GinkgoT()))
+		mockClient.EXPECT().
+			DownloadArtifact(gomock.Any(), gomock.Any(), "broken", gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, fmt.Errorf("download error"))
+		mockClient.EXPECT().
+			DownloadArtifact(gomock.Any(), gomock.Any(), "healthy", gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(bytes.NewReader([]byte("apiVersion: v1\nkind: ConfigMap")), nil)
+
+		reconciler := &InfrahubSyncReconciler{Client: fakeClient, Scheme: scheme, InfrahubClient: mockClient}
+		artifacts := []domain.Artifact{{ID: "broken", Checksum: "new"}, {ID: "healthy"}}
+		_, synced, err := reconciler.processArtifacts(ctx, mockClient, infrahubSync, &artifacts, domain.Token{})
+
+		Expect(err).To(MatchError("artifact broken: failed to download artifact: download error"))
+		var condErr *ConditionError
+		Expect(goerrors.As(err, &condErr)).To(BeTrue())
+		Expect(condErr.Reason).To(Equal(infrahubv1alpha1.ReasonDownloadFailed))
+
+		Expect(synced).To(HaveLen(2))
+		Expect(synced[0]).To(Equal(previous))
+		Expect(synced[1].ArtifactID).To(Equal("healthy"))
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "healthy"}, &infrahubv1alpha1.VidraResource{})).To(Succeed())
+	})
+})
+
 var _ = Describe("InfrahubSyncReconciler SetupWithManager", func() {
 	var (
 		mgr        manager.Manager
@@ -802,8 +1230,17 @@ var _ = Describe("InfrahubSyncReconciler SetupWithManager", func() {
 				},
 			},
 			Data: map[string]string{
-				"requeueSyncAfter": "10m",
-				"queryName":        "ArtifactIDs",
+				"requeueSyncAfter":       "10m",
+				"queryName":              "ArtifactIDs",
+				"infrahubMaxRetries":     "2",
+				"infrahubRetryBackoff":   "1s",
+				"infrahubRequestTimeout": "30s",
+				"infrahubRateLimit":      "5.5",
+				"infrahubRateBurst":      "10",
+				"infrahubMaxInFlight":    "0",
+				"maxArtifactSize":        "20Mi",
+				"inlineManifestLimit":    "256Ki",
+				"manifestChunkNamespace": "default",
 			},
 		}
 		err := k8sClient.Create(ctx, configMap)
@@ -826,6 +1263,27 @@ var _ = Describe("InfrahubSyncReconciler SetupWithManager", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(reconciler.RequeueAfter).To(Equal(10 * time.Minute))
 		Expect(reconciler.QueryName).To(Equal("ArtifactIDs"))
+		Expect(reconciler.ClientOptions).To(Equal(infrahub.ClientOptions{
+			MaxRetries:     2,
+			RetryBackoff:   time.Second,
+			RequestTimeout: 30 * time.Second,
+			RateLimit:      5.5,
+			RateBurst:      10,
+			MaxInFlight:    0,
+		}))
+		Expect(reconciler.MaxArtifactSize).To(Equal(int64(20 << 20)))
+		Expect(reconciler.InlineManifestLimit).To(Equal(int64(256 << 10)))
+		Expect(reconciler.ManifestChunkNamespace).To(Equal("default"))
 	})
 
 })
+
+var _ = Describe("Selecting the config ConfigMap", func() {
+	It("should only select data that sets a known config key", func() {
+		Expect(hasConfigKey(map[string]string{"manifestChunkNamespace": "default"})).To(BeTrue())
+		Expect(hasConfigKey(map[string]string{"other": "value", "queryName": "ArtifactIDs"})).To(BeTrue())
+		Expect(hasConfigKey(map[string]string{"queryName": ""})).To(BeFalse())
+		Expect(hasConfigKey(map[string]string{"other": "value"})).To(BeFalse())
+		Expect(hasConfigKey(nil)).To(BeFalse())
+	})
+})
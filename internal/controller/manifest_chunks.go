@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+)
+
+const (
+	// DefaultMaxArtifactSize limits the size of a downloaded artifact
+	DefaultMaxArtifactSize int64 = 10 << 20
+	// DefaultInlineManifestLimit is the size up to which a manifest is stored inline in the VidraResource
+	DefaultInlineManifestLimit int64 = 512 << 10
+	// DefaultManifestChunkNamespace is the namespace of the Secrets holding the chunks of large manifests
+	DefaultManifestChunkNamespace = "vidra-system"
+
+	// manifestChunkSize keeps every chunk Secret well below the 1MiB size limit of Kubernetes objects
+	manifestChunkSize = 512 << 10
+	// manifestChunkKey is the key of the chunk in the data of a chunk Secret
+	manifestChunkKey = "manifest"
+	// ManifestChunkLabel marks the chunk Secrets of a VidraResource with its name
+	ManifestChunkLabel = "vidra.infrahub.operators.com/manifest-of"
+)
+
+// readArtifact reads the content of the artifact and stops as soon as it exceeds maxSize, so an oversized artifact
+// is never held in memory completely
+func readArtifact(reader io.Reader, maxSize int64) (string, error) {
+	var sb strings.Builder
+	n, err := io.Copy(&sb, io.LimitReader(reader, maxSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read artifact content: %w", err)
+	}
+	if n > maxSize {
+		return "", NewConditionError(infrahubv1alpha1.ConditionArtifactsFetched, infrahubv1alpha1.ReasonArtifactTooLarge,
+			fmt.Errorf("content exceeds the maximum artifact size of %d bytes", maxSize))
+	}
+	return sb.String(), nil
+}
+
+// splitManifest splits the manifest into chunks of at most size bytes
+func splitManifest(manifest string, size int) []string {
+	var chunks []string
+	for len(manifest) > size {
+		chunks = append(chunks, manifest[:size])
+		manifest = manifest[size:]
+	}
+	return append(chunks, manifest)
+}
+
+// manifestChunkName returns the name of the Secret holding the chunk with the index. The name contains the start of
+// the checksum of the manifest, so writing the chunks of a new manifest leaves the chunks of the current one intact.
+func manifestChunkName(resourceName, sha256 string, index int) string {
+	return fmt.Sprintf("%s-manifest-%.10s-%d", resourceName, sha256, index)
+}
+
+// manifestSHA256 returns the hex encoded SHA-256 of the manifest
+func manifestSHA256(manifest string) string {
+	sum := sha256.Sum256([]byte(manifest))
+	return hex.EncodeToString(sum[:])
+}
+
+// writeManifestChunks writes the chunks of the manifest with the checksum into Secrets labeled with the name of the
+// VidraResource. They are owned by the VidraResource once it has a UID, so they are garbage collected with it.
+func writeManifestChunks(ctx context.Context, c client.Client, res *infrahubv1alpha1.VidraResource, namespace, sha256 string, chunks []string) error {
+	for i, chunk := range chunks {
+		secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: manifestChunkName(res.Name, sha256, i), Namespace: namespace}}
+		if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			_, err := ctrl.CreateOrUpdate(ctx, c, secret, func() error {
+				if secret.Labels == nil {
+					secret.Labels = map[string]string{}
+				}
+				secret.Labels[ManifestChunkLabel] = res.Name
+				secret.Data = map[string][]byte{manifestChunkKey: []byte(chunk)}
+				if res.UID == "" {
+					return nil
+				}
+				return controllerutil.SetOwnerReference(res, secret, c.Scheme())
+			})
+			return err
+		}); err != nil {
+			return fmt.Errorf("failed to store manifest chunk %s/%s: %w", namespace, secret.Name, err)
+		}
+	}
+	return nil
+}
+
+// storeManifestChunks writes the chunks of the manifest of the VidraResource into Secrets owned by it and deletes
+// all other chunk Secrets of the VidraResource, e.g. of a previous manifest. Without chunks all chunk Secrets of the
+// VidraResource are deleted.
+func storeManifestChunks(ctx context.Context, c client.Client, res *infrahubv1alpha1.VidraResource, namespace, sha256 string, chunks []string) error {
+	if err := writeManifestChunks(ctx, c, res, namespace, sha256, chunks); err != nil {
+		return err
+	}
+
+	var secrets v1.SecretList
+	if err := c.List(ctx, &secrets, client.InNamespace(namespace), client.MatchingLabels{ManifestChunkLabel: res.Name}); err != nil {
+		return fmt.Errorf("failed to list manifest chunks: %w", err)
+	}
+	current := make(map[string]struct{}, len(chunks))
+	for i := range chunks {
+		current[manifestChunkName(res.Name, sha256, i)] = struct{}{}
+	}
+	for _, secret := range secrets.Items {
+		if _, ok := current[secret.Name]; ok {
+			continue
+		}
+		if err := c.Delete(ctx, &secret); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete manifest chunk %s/%s: %w", namespace, secret.Name, err)
+		}
+	}
+	return nil
+}
+
+// loadManifest returns the inline manifest of the VidraResource or joins the chunks it references
+func loadManifest(ctx context.Context, c client.Client, res *infrahubv1alpha1.VidraResource) (string, error) {
+	source := res.Spec.ManifestFrom
+	if source == nil {
+		return res.Spec.Manifest, nil
+	}
+
+	var sb strings.Builder
+	for _, name := range source.Secrets {
+		var secret v1.Secret
+		if err := c.Get(ctx, types.NamespacedName{Namespace: source.Namespace, Name: name}, &secret); err != nil {
+			return "", fmt.Errorf("failed to get manifest chunk %s/%s: %w", source.Namespace, name, err)
+		}
+		sb.Write(secret.Data[manifestChunkKey])
+	}
+
+	// The chunks are written before the VidraResource references them, a mismatch means they were modified
+	manifest := sb.String()
+	if sum := manifestSHA256(manifest); sum != source.SHA256 {
+		return "", fmt.Errorf("manifest chunks do not match the manifest checksum %s, got %s", source.SHA256, sum)
+	}
+	return manifest, nil
+}
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+)
+
+var _ = Describe("Manifest chunks", func() {
+	var (
+		ctx        = context.Background()
+		fakeClient client.Client
+		res        *infrahubv1alpha1.VidraResource
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(infrahubv1alpha1.AddToScheme(scheme)).To(Succeed())
+		res = &infrahubv1alpha1.VidraResource{ObjectMeta: metav1.ObjectMeta{Name: "artifact-1", UID: "uid-1"}}
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(res).Build()
+	})
+
+	// store writes the manifest in chunks of size bytes and points the VidraResource to them
+	store := func(manifest string, size int) {
+		chunks := splitManifest(manifest, size)
+		sum := manifestSHA256(manifest)
+		Expect(storeManifestChunks(ctx, fakeClient, res, "vidra-system", sum, chunks)).To(Succeed())
+		res.Spec.ManifestFrom = &infrahubv1alpha1.ManifestSource{Namespace: "vidra-system", SHA256: sum}
+		for i := range chunks {
+			res.Spec.ManifestFrom.Secrets = append(res.Spec.ManifestFrom.Secrets, manifestChunkName(res.Name, sum, i))
+		}
+	}
+	chunkSecrets := func() []string {
+		var secrets v1.SecretList
+		Expect(fakeClient.List(ctx, &secrets, client.MatchingLabels{ManifestChunkLabel: res.Name})).To(Succeed())
+		var names []string
+		for _, secret := range secrets.Items {
+			names = append(names, secret.Name)
+		}
+		return names
+	}
+
+	It("should stop reading artifacts exceeding the maximum size", func() {
+		content, err := readArtifact(strings.NewReader("0123456789"), 10)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(Equal("0123456789"))
+
+		_, err = readArtifact(strings.NewReader("0123456789a"), 10)
+		Expect(err).To(MatchError("content exceeds the maximum artifact size of 10 bytes"))
+		var condErr *ConditionError
+		Expect(errors.As(err, &condErr)).To(BeTrue())
+		Expect(condErr.Reason).To(Equal(infrahubv1alpha1.ReasonArtifactTooLarge))
+	})
+
+	It("should split manifests into chunks of the given size", func() {
+		Expect(splitManifest("abcdefg", 3)).To(Equal([]string{"abc", "def", "g"}))
+		Expect(splitManifest("abc", 3)).To(Equal([]string{"abc"}))
+	})
+
+	It("should join the chunks owned by the VidraResource", func() {
+		store("kind: ConfigMap\nkind: Secret\n", 8)
+		Expect(chunkSecrets()).To(HaveLen(4))
+
+		var secret v1.Secret
+		name := manifestChunkName(res.Name, res.Spec.ManifestFrom.SHA256, 0)
+		Expect(name).To(Equal("artifact-1-manifest-" + res.Spec.ManifestFrom.SHA256[:10] + "-0"))
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "vidra-system", Name: name}, &secret)).To(Succeed())
+		Expect(secret.OwnerReferences).To(ConsistOf(HaveField("UID", res.UID)))
+
+		manifest, err := loadManifest(ctx, fakeClient, res)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest).To(Equal("kind: ConfigMap\nkind: Secret\n"))
+	})
+
+	It("should delete the chunks of a previous manifest", func() {
+		store("kind: ConfigMap\nkind: Secret\n", 8)
+		store("kind: ConfigMap\n", 8)
+		Expect(chunkSecrets()).To(ConsistOf(res.Spec.ManifestFrom.Secrets))
+
+		Expect(storeManifestChunks(ctx, fakeClient, res, "vidra-system", "", nil)).To(Succeed())
+		Expect(chunkSecrets()).To(BeEmpty())
+	})
+
+	It("should keep the current manifest readable while the chunks of the next one are written", func() {
+		store("kind: ConfigMap\n", 8)
+		next := "kind: Secret\nkind: Service\n"
+		Expect(writeManifestChunks(ctx, fakeClient, res, "vidra-system", manifestSHA256(next), splitManifest(next, 8))).To(Succeed())
+		Expect(loadManifest(ctx, fakeClient, res)).To(Equal("kind: ConfigMap\n"))
+
+		store(next, 8)
+		Expect(loadManifest(ctx, fakeClient, res)).To(Equal(next))
+		Expect(chunkSecrets()).To(ConsistOf(res.Spec.ManifestFrom.Secrets))
+	})
+
+	It("should own the chunks written before the VidraResource was created once it has a UID", func() {
+		created := &infrahubv1alpha1.VidraResource{ObjectMeta: metav1.ObjectMeta{Name: "artifact-2"}}
+		sum := manifestSHA256("kind: ConfigMap\n")
+		Expect(writeManifestChunks(ctx, fakeClient, created, "vidra-system", sum, []string{"kind: ConfigMap\n"})).To(Succeed())
+
+		var secret v1.Secret
+		key := types.NamespacedName{Namespace: "vidra-system", Name: manifestChunkName(created.Name, sum, 0)}
+		Expect(fakeClient.Get(ctx, key, &secret)).To(Succeed())
+		Expect(secret.OwnerReferences).To(BeEmpty())
+
+		created.UID = "uid-2"
+		Expect(storeManifestChunks(ctx, fakeClient, created, "vidra-system", sum, []string{"kind: ConfigMap\n"})).To(Succeed())
+		Expect(fakeClient.Get(ctx, key, &secret)).To(Succeed())
+		Expect(secret.OwnerReferences).To(ConsistOf(HaveField("UID", created.UID)))
+	})
+
+	It("should reject chunks not matching the manifest checksum", func() {
+		store("kind: ConfigMap\n", 8)
+		res.Spec.ManifestFrom.SHA256 = manifestSHA256("kind: Secret\n")
+
+		_, err := loadManifest(ctx, fakeClient, res)
+		Expect(err).To(MatchError(ContainSubstring("manifest chunks do not match the manifest checksum")))
+	})
+
+	It("should return the inline manifest without chunks", func() {
+		res.Spec.Manifest = "kind: ConfigMap"
+		Expect(loadManifest(ctx, fakeClient, res)).To(Equal("kind: ConfigMap"))
+	})
+})
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PruneAnnotation set to "false" on a managed resource or VidraResource prevents it from being deleted by the operator
+const PruneAnnotation = "vidra.infrahub.operators.com/prune"
+
+// pruneOutcome describes why stale resources were kept, the reason is empty if every stale resource was pruned
+type pruneOutcome struct {
+	reason  string
+	message string
+}
+
+// prunePolicy returns the prune policy of the destination, Delete if it is not set
+func prunePolicy(dest infrahubv1alpha1.InfrahubSyncDestination) infrahubv1alpha1.PrunePolicy {
+	if dest.PrunePolicy == "" {
+		return infrahubv1alpha1.PrunePolicyDelete
+	}
+	return dest.PrunePolicy
+}
+
+// pruneDisabled reports whether the object is excluded from pruning by the prune annotation
+func pruneDisabled(obj metav1.Object) bool {
+	return obj.GetAnnotations()[PruneAnnotation] == "false"
+}
+
+// checkPrune decides whether stale out of total resources may be pruned according to the destination.
+// The outcome is empty if pruning may proceed.
+func checkPrune(dest infrahubv1alpha1.InfrahubSyncDestination, stale, total int, kind string) pruneOutcome {
+	if stale == 0 {
+		return pruneOutcome{}
+	}
+	if prunePolicy(dest) == infrahubv1alpha1.PrunePolicyDisabled {
+		return pruneOutcome{
+			reason:  infrahubv1alpha1.ReasonPruneDisabled,
+			message: fmt.Sprintf("%d stale %s kept, pruning is disabled", stale, kind),
+		}
+	}
+	if dest.MaxPrunePercent > 0 && total > 0 && stale*100 > int(dest.MaxPrunePercent)*total {
+		return pruneOutcome{
+			reason: infrahubv1alpha1.ReasonPruneThresholdExceeded,
+			message: fmt.Sprintf("refusing to prune %d of %d %s, more than maxPrunePercent (%d%%)",
+				stale, total, kind, dest.MaxPrunePercent),
+		}
+	}
+	return pruneOutcome{}
+}
+
+// setPruneConditions sets the Pruned and Ready conditions from the outcome of a prune pass.
+// A prune pass refused by the threshold needs attention, so the resource is not ready and its state is stale.
+func setPruneConditions(res client.Object, outcome pruneOutcome) infrahubv1alpha1.State {
+	switch outcome.reason {
+	case "":
+		SetCondition(res, infrahubv1alpha1.ConditionPruned, metav1.ConditionTrue, infrahubv1alpha1.ReasonSucceeded, "No stale resources left")
+	case infrahubv1alpha1.ReasonPruneThresholdExceeded:
+		SetCondition(res, infrahubv1alpha1.ConditionPruned, metav1.ConditionFalse, outcome.reason, outcome.message)
+		SetCondition(res, infrahubv1alpha1.ConditionReady, metav1.ConditionFalse, outcome.reason, outcome.message)
+		return infrahubv1alpha1.StateStale
+	default:
+		SetCondition(res, infrahubv1alpha1.ConditionPruned, metav1.ConditionFalse, outcome.reason, outcome.message)
+	}
+	SetCondition(res, infrahubv1alpha1.ConditionReady, metav1.ConditionTrue, infrahubv1alpha1.ReasonSucceeded, "Reconciliation succeeded")
+	return infrahubv1alpha1.StateSucceeded
+}
+
+// releaseManagedResource removes the ownership of the VidraResource from the object without deleting it.
+// Objects without any other owner are no longer marked as managed by the operator.
+func releaseManagedResource(ctx context.Context, res *infrahubv1alpha1.VidraResource, obj *unstructured.Unstructured, destClient client.Client) error {
+	key := client.ObjectKeyFromObject(obj)
+	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err := destClient.Get(ctx, key, obj); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		releaseOwnership(res, obj)
+		return destClient.Update(ctx, obj, client.FieldOwner(FieldManager))
+	}); err != nil {
+		return fmt.Errorf("failed to release resource %s: %w", obj.GetName(), err)
+	}
+	return nil
+}
+
+// releaseOwnership removes the VidraResource from the owner annotation and owner references of the object.
+// The apply entry of its field manager is dropped, if no other owner is left the managed-by annotation and the apply
+// entries of all vidra field managers are removed as well.
+func releaseOwnership(res *infrahubv1alpha1.VidraResource, obj *unstructured.Unstructured) {
+	annotations := obj.GetAnnotations()
+	owners := removeString(strings.Split(annotations[OwnerAnnotation], ","), res.Name)
+	if len(owners) == 0 || (len(owners) == 1 && owners[0] == "") {
+		delete(annotations, OwnerAnnotation)
+		delete(annotations, "managed-by")
+		obj.SetManagedFields(withoutApplyEntries(obj.GetManagedFields(), isVidraFieldManager))
+	} else {
+		annotations[OwnerAnnotation] = strings.Join(owners, ",")
+		obj.SetManagedFields(withoutApplyEntries(obj.GetManagedFields(), func(manager string) bool {
+			return manager == fieldManagerFor(res)
+		}))
+	}
+	obj.SetAnnotations(annotations)
+
+	var refs []metav1.OwnerReference
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID != res.UID {
+			refs = append(refs, ref)
+		}
+	}
+	obj.SetOwnerReferences(refs)
+}
+
+// withoutApplyEntries returns the managed fields without the server-side apply entries of the matching field managers.
+// The API server ignores an empty list, a single empty entry clears the managed fields instead.
+func withoutApplyEntries(entries []metav1.ManagedFieldsEntry, matches func(manager string) bool) []metav1.ManagedFieldsEntry {
+	var kept []metav1.ManagedFieldsEntry
+	for _, entry := range entries {
+		if matches(entry.Manager) && entry.Operation == metav1.ManagedFieldsOperationApply {
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	if len(kept) == 0 {
+		return []metav1.ManagedFieldsEntry{{}}
+	}
+	return kept
+}
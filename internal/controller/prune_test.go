@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+)
+
+var _ = Describe("Prune policy", func() {
+	It("should allow pruning by default", func() {
+		outcome := checkPrune(infrahubv1alpha1.InfrahubSyncDestination{}, 3, 3, "resources")
+		Expect(outcome.reason).To(BeEmpty())
+	})
+
+	It("should keep stale resources if pruning is disabled", func() {
+		outcome := checkPrune(infrahubv1alpha1.InfrahubSyncDestination{
+			PrunePolicy: infrahubv1alpha1.PrunePolicyDisabled,
+		}, 1, 3, "resources")
+		Expect(outcome.reason).To(Equal(infrahubv1alpha1.ReasonPruneDisabled))
+		Expect(outcome.message).To(Equal("1 stale resources kept, pruning is disabled"))
+	})
+
+	It("should refuse to prune more than maxPrunePercent of the managed resources", func() {
+		dest := infrahubv1alpha1.InfrahubSyncDestination{MaxPrunePercent: 50}
+		Expect(checkPrune(dest, 2, 4, "resources").reason).To(BeEmpty())
+
+		outcome := checkPrune(dest, 3, 4, "VidraResources")
+		Expect(outcome.reason).To(Equal(infrahubv1alpha1.ReasonPruneThresholdExceeded))
+		Expect(outcome.message).To(Equal("refusing to prune 3 of 4 VidraResources, more than maxPrunePercent (50%)"))
+	})
+
+	It("should not report anything if nothing is stale", func() {
+		outcome := checkPrune(infrahubv1alpha1.InfrahubSyncDestination{
+			PrunePolicy:     infrahubv1alpha1.PrunePolicyDisabled,
+			MaxPrunePercent: 1,
+		}, 0, 4, "resources")
+		Expect(outcome.reason).To(BeEmpty())
+	})
+
+	It("should only mark the resource as not ready if the threshold is exceeded", func() {
+		res := &infrahubv1alpha1.VidraResource{}
+		state := setPruneConditions(res, pruneOutcome{reason: infrahubv1alpha1.ReasonPruneDisabled, message: "kept"})
+		Expect(state).To(Equal(infrahubv1alpha1.StateSucceeded))
+		Expect(meta.IsStatusConditionFalse(res.Status.Conditions, infrahubv1alpha1.ConditionPruned)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(res.Status.Conditions, infrahubv1alpha1.ConditionReady)).To(BeTrue())
+
+		state = setPruneConditions(res, pruneOutcome{reason: infrahubv1alpha1.ReasonPruneThresholdExceeded, message: "refused"})
+		Expect(state).To(Equal(infrahubv1alpha1.StateStale))
+		ready := meta.FindStatusCondition(res.Status.Conditions, infrahubv1alpha1.ConditionReady)
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal(infrahubv1alpha1.ReasonPruneThresholdExceeded))
+	})
+
+	It("should no longer mark a released resource without other owners as managed", func() {
+		res := &infrahubv1alpha1.VidraResource{ObjectMeta: metav1.ObjectMeta{Name: "web", UID: "web-uid"}}
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("ConfigMap")
+		obj.SetNamespace("default")
+		obj.SetName("example")
+		obj.SetAnnotations(map[string]string{OwnerAnnotation: "web", "managed-by": vidraOperator})
+		obj.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "v1", Kind: "VidraResource", Name: "web", UID: "web-uid"}})
+		obj.SetManagedFields([]metav1.ManagedFieldsEntry{
+			{Manager: fieldManagerFor(res), Operation: metav1.ManagedFieldsOperationApply, APIVersion: "v1"},
+			{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationUpdate, APIVersion: "v1"},
+		})
+		destClient := fake.NewClientBuilder().WithObjects(obj).Build()
+
+		Expect(releaseManagedResource(context.Background(), res, obj.DeepCopy(), destClient)).To(Succeed())
+
+		released := &unstructured.Unstructured{}
+		released.SetAPIVersion("v1")
+		released.SetKind("ConfigMap")
+		Expect(destClient.Get(context.Background(), client.ObjectKeyFromObject(obj), released)).To(Succeed())
+		Expect(released.GetAnnotations()).ToNot(HaveKey(OwnerAnnotation))
+		Expect(released.GetOwnerReferences()).To(BeEmpty())
+		Expect(isManagedByVidra(released)).To(BeFalse())
+	})
+
+	It("should keep a released resource managed while other VidraResources own it", func() {
+		res := &infrahubv1alpha1.VidraResource{ObjectMeta: metav1.ObjectMeta{Name: "web"}}
+		obj := &unstructured.Unstructured{}
+		obj.SetAnnotations(map[string]string{OwnerAnnotation: "web,db", "managed-by": vidraOperator})
+		obj.SetManagedFields([]metav1.ManagedFieldsEntry{
+			{Manager: "vidra/web", Operation: metav1.ManagedFieldsOperationApply},
+			{Manager: "vidra/db", Operation: metav1.ManagedFieldsOperationApply},
+		})
+
+		releaseOwnership(res, obj)
+
+		Expect(obj.GetAnnotations()).To(HaveKeyWithValue(OwnerAnnotation, "db"))
+		Expect(obj.GetManagedFields()).To(ConsistOf(HaveField("Manager", "vidra/db")))
+		Expect(isManagedByVidra(obj)).To(BeTrue())
+	})
+})
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// defaultRetryBackoff is the delay before the first retry of a failed sync
+	defaultRetryBackoff = 10 * time.Second
+	// defaultRetryMaxBackoff is the upper bound of the delay between retries of a failed sync
+	defaultRetryMaxBackoff = 5 * time.Minute
+)
+
+// syncInterval returns the interval of the sync, the operator wide default if it is not set in the spec
+func syncInterval(infrahubSync *infrahubv1alpha1.InfrahubSync, defaultInterval time.Duration) time.Duration {
+	if infrahubSync.Spec.SyncInterval != nil && infrahubSync.Spec.SyncInterval.Duration > 0 {
+		return infrahubSync.Spec.SyncInterval.Duration
+	}
+	return defaultInterval
+}
+
+// retryBackoff returns the delay before the next retry after the given number of consecutive failures.
+// The delay doubles with every failure up to the max backoff. It returns false if the retry limit is exhausted.
+func retryBackoff(retry *infrahubv1alpha1.RetryStrategy, failures int32) (time.Duration, bool) {
+	backoff, maxBackoff := defaultRetryBackoff, defaultRetryMaxBackoff
+	if retry != nil {
+		if retry.Limit > 0 && failures > retry.Limit {
+			return 0, false
+		}
+		if retry.Backoff != nil && retry.Backoff.Duration > 0 {
+			backoff = retry.Backoff.Duration
+		}
+		if retry.MaxBackoff != nil && retry.MaxBackoff.Duration > 0 {
+			maxBackoff = retry.MaxBackoff.Duration
+		}
+	}
+
+	delay := backoff
+	for i := int32(1); i < failures && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff), true
+}
+
+// withJitter adds a random delay of up to the given percentage to the duration
+func withJitter(d time.Duration, percent int32) time.Duration {
+	maxJitter := int64(d) * int64(percent) / 100
+	if maxJitter <= 0 {
+		return d
+	}
+	return d + time.Duration(rand.Int63n(maxJitter+1))
+}
+
+// nextSync returns the delay until the next sync after the given number of consecutive failures
+func nextSync(infrahubSync *infrahubv1alpha1.InfrahubSync, defaultInterval time.Duration, failures int32) time.Duration {
+	delay := syncInterval(infrahubSync, defaultInterval)
+	if failures > 0 {
+		if backoff, ok := retryBackoff(infrahubSync.Spec.Retry, failures); ok {
+			delay = backoff
+		}
+	}
+	return withJitter(delay, infrahubSync.Spec.JitterPercent)
+}
+
+// nextSyncTime returns the time of the next sync, zero if the sync is not requeued
+func nextSyncTime(requeueAfter time.Duration) metav1.Time {
+	if requeueAfter <= 0 {
+		return metav1.Time{}
+	}
+	return metav1.NewTime(time.Now().Add(requeueAfter))
+}
+
+// retryRateLimiter requeues failed syncs after the backoff computed from their retry strategy.
+// Requests without a scheduled retry use the default rate limiter of controller-runtime.
+type retryRateLimiter struct {
+	delays   sync.Map
+	fallback workqueue.TypedRateLimiter[reconcile.Request]
+}
+
+func newRetryRateLimiter() *retryRateLimiter {
+	return &retryRateLimiter{fallback: workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]()}
+}
+
+// schedule records the delay of the next retry of the request
+func (l *retryRateLimiter) schedule(req reconcile.Request, delay time.Duration) {
+	if l != nil {
+		l.delays.Store(req, delay)
+	}
+}
+
+func (l *retryRateLimiter) When(req reconcile.Request) time.Duration {
+	if delay, ok := l.delays.LoadAndDelete(req); ok {
+		return delay.(time.Duration)
+	}
+	return l.fallback.When(req)
+}
+
+func (l *retryRateLimiter) Forget(req reconcile.Request) {
+	l.delays.Delete(req)
+	l.fallback.Forget(req)
+}
+
+func (l *retryRateLimiter) NumRequeues(req reconcile.Request) int {
+	return l.fallback.NumRequeues(req)
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+)
+
+var _ = Describe("Sync retry", func() {
+	It("should double the default backoff up to the default max backoff", func() {
+		var delays []time.Duration
+		for failures := int32(1); failures <= 7; failures++ {
+			delay, ok := retryBackoff(nil, failures)
+			Expect(ok).To(BeTrue())
+			delays = append(delays, delay)
+		}
+		Expect(delays).To(Equal([]time.Duration{
+			10 * time.Second, 20 * time.Second, 40 * time.Second, 80 * time.Second,
+			160 * time.Second, 5 * time.Minute, 5 * time.Minute,
+		}))
+	})
+
+	It("should stop backing off once the retry limit is exhausted", func() {
+		retry := &infrahubv1alpha1.RetryStrategy{Limit: 2, Backoff: &metav1.Duration{Duration: time.Second}}
+		delay, ok := retryBackoff(retry, 2)
+		Expect(ok).To(BeTrue())
+		Expect(delay).To(Equal(2 * time.Second))
+
+		_, ok = retryBackoff(retry, 3)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should use the sync interval of the spec and fall back to the operator default", func() {
+		sync := &infrahubv1alpha1.InfrahubSync{}
+		Expect(nextSync(sync, time.Minute, 0)).To(Equal(time.Minute))
+
+		sync.Spec.SyncInterval = &metav1.Duration{Duration: time.Hour}
+		Expect(nextSync(sync, time.Minute, 0)).To(Equal(time.Hour))
+		Expect(nextSync(sync, time.Minute, 1)).To(Equal(defaultRetryBackoff))
+	})
+
+	It("should add a jitter of up to the given percentage", func() {
+		for i := 0; i < 20; i++ {
+			Expect(withJitter(time.Minute, 10)).To(BeNumerically("~", 63*time.Second, 3*time.Second))
+		}
+		Expect(withJitter(time.Minute, 0)).To(Equal(time.Minute))
+	})
+
+	It("should not schedule a next sync if requeueing is disabled", func() {
+		Expect(nextSyncTime(0).Time.IsZero()).To(BeTrue())
+		Expect(nextSyncTime(time.Minute).Time).To(BeTemporally("~", time.Now().Add(time.Minute), time.Second))
+	})
+
+	It("should requeue failed syncs after the scheduled backoff once", func() {
+		limiter := newRetryRateLimiter()
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "sync"}}
+
+		limiter.schedule(req, 42*time.Second)
+		Expect(limiter.When(req)).To(Equal(42 * time.Second))
+		Expect(limiter.When(req)).To(BeNumerically("<", time.Second))
+
+		limiter.schedule(req, 42*time.Second)
+		limiter.Forget(req)
+		Expect(limiter.When(req)).To(BeNumerically("<", time.Second))
+	})
+})
@@ -2,9 +2,11 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -13,6 +15,103 @@ import (
 
 // Sync and Resource state helpers
 
+// ConditionError is an error which carries the condition type and reason describing the failure.
+// MarkStateFailed uses it to set the matching status condition besides the Ready condition.
+type ConditionError struct {
+	ConditionType string
+	Reason        string
+	Err           error
+}
+
+func (e *ConditionError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConditionError) Unwrap() error {
+	return e.Err
+}
+
+// NewConditionError wraps the error with the condition type and reason of the failure.
+// Errors which already carry a condition are returned unchanged, so the innermost failure wins.
+func NewConditionError(conditionType, reason string, err error) error {
+	var condErr *ConditionError
+	if errors.As(err, &condErr) {
+		return err
+	}
+	return &ConditionError{ConditionType: conditionType, Reason: reason, Err: err}
+}
+
+// SetCondition sets the condition on the status of the resource and records the generation it was observed for.
+func SetCondition(res client.Object, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	conditions := statusConditions(res)
+	if conditions == nil {
+		return
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: res.GetGeneration(),
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// MarkReconciling sets the Ready condition to Unknown if the current generation was not yet reconciled.
+func MarkReconciling(res client.Object) {
+	if observedGeneration(res) != res.GetGeneration() {
+		SetCondition(res, infrahubv1alpha1.ConditionReady, metav1.ConditionUnknown, infrahubv1alpha1.ReasonReconciling,
+			fmt.Sprintf("Reconciling generation %d", res.GetGeneration()))
+	}
+}
+
+// MarkSuspended sets the Suspended condition. It is only added once a resource was suspended.
+func MarkSuspended(res client.Object, suspended bool) {
+	if suspended {
+		SetCondition(res, infrahubv1alpha1.ConditionSuspended, metav1.ConditionTrue, infrahubv1alpha1.ReasonSuspended,
+			"Reconciliation is suspended by spec.suspend")
+		return
+	}
+	if conditions := statusConditions(res); conditions != nil && meta.IsStatusConditionTrue(*conditions, infrahubv1alpha1.ConditionSuspended) {
+		SetCondition(res, infrahubv1alpha1.ConditionSuspended, metav1.ConditionFalse, infrahubv1alpha1.ReasonResumed, "Reconciliation is resumed")
+	}
+}
+
+// MarkObserved records the current generation of the resource as observed.
+func MarkObserved(res client.Object) {
+	switch obj := res.(type) {
+	case *infrahubv1alpha1.VidraResource:
+		obj.Status.ObservedGeneration = obj.Generation
+	case *infrahubv1alpha1.InfrahubSync:
+		obj.Status.ObservedGeneration = obj.Generation
+	case *infrahubv1alpha1.VidraCluster:
+		obj.Status.ObservedGeneration = obj.Generation
+	}
+}
+
+func observedGeneration(res client.Object) int64 {
+	switch obj := res.(type) {
+	case *infrahubv1alpha1.VidraResource:
+		return obj.Status.ObservedGeneration
+	case *infrahubv1alpha1.InfrahubSync:
+		return obj.Status.ObservedGeneration
+	case *infrahubv1alpha1.VidraCluster:
+		return obj.Status.ObservedGeneration
+	}
+	return 0
+}
+
+func statusConditions(res client.Object) *[]metav1.Condition {
+	switch obj := res.(type) {
+	case *infrahubv1alpha1.VidraResource:
+		return &obj.Status.Conditions
+	case *infrahubv1alpha1.InfrahubSync:
+		return &obj.Status.Conditions
+	case *infrahubv1alpha1.VidraCluster:
+		return &obj.Status.Conditions
+	}
+	return nil
+}
+
 // MarkSyncState updates the resource's SyncState using the provided update function.
 // It retries on conflict using the default backoff strategy.
 func MarkState(
@@ -41,11 +140,14 @@ func MarkState(
 
 // MarkSyncFailed sets the resource's SyncState to Failed and logs the error.
 // It calls MarkSyncState to handle the update, retrying on conflict.
+// If the error is a ConditionError, the matching condition is set to False as well.
+// Additional status updates are patched together with the failure.
 func MarkStateFailed(
 	ctx context.Context,
 	c client.StatusClient,
 	res client.Object,
 	originalErr error,
+	updateStatus ...func(),
 ) error {
 	logger := log.FromContext(ctx)
 	if res == nil {
@@ -65,6 +167,19 @@ func MarkStateFailed(
 		default:
 			// Log unsupported resource type error
 			logger.Error(fmt.Errorf("unsupported resource type"), "failed to update resource status")
+			return
+		}
+
+		reason := infrahubv1alpha1.ReasonFailed
+		var condErr *ConditionError
+		if errors.As(originalErr, &condErr) {
+			reason = condErr.Reason
+			SetCondition(res, condErr.ConditionType, metav1.ConditionFalse, reason, originalErr.Error())
+		}
+		SetCondition(res, infrahubv1alpha1.ConditionReady, metav1.ConditionFalse, reason, originalErr.Error())
+		MarkObserved(res)
+		for _, update := range updateStatus {
+			update()
 		}
 	})
 
@@ -2,9 +2,12 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -56,3 +59,92 @@ var _ = Describe("MarkState and MarkStateFailed with real client", func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 })
+
+var _ = Describe("Status conditions", func() {
+	var (
+		ctx      context.Context
+		vidraRes *infrahubv1alpha1.VidraResource
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		vidraRes = &infrahubv1alpha1.VidraResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-conditions",
+			},
+		}
+		Expect(k8sClient.Create(ctx, vidraRes)).To(Succeed())
+	})
+	AfterEach(func() {
+		_ = k8sClient.Delete(ctx, vidraRes)
+	})
+
+	It("should keep the innermost condition when wrapping a ConditionError again", func() {
+		inner := NewConditionError(infrahubv1alpha1.ConditionApplied, infrahubv1alpha1.ReasonInvalidManifest, fmt.Errorf("decode artifact"))
+		outer := NewConditionError(infrahubv1alpha1.ConditionApplied, infrahubv1alpha1.ReasonApplyFailed, inner)
+
+		var condErr *ConditionError
+		Expect(errors.As(outer, &condErr)).To(BeTrue())
+		Expect(condErr.Reason).To(Equal(infrahubv1alpha1.ReasonInvalidManifest))
+		Expect(outer.Error()).To(Equal("decode artifact"))
+	})
+
+	It("should set the failed condition and Ready to False in MarkStateFailed", func() {
+		err := MarkStateFailed(ctx, k8sClient, vidraRes,
+			NewConditionError(infrahubv1alpha1.ConditionPruned, infrahubv1alpha1.ReasonPruneFailed, fmt.Errorf("delete failed")))
+		Expect(err).To(MatchError("delete failed"))
+
+		updated := &infrahubv1alpha1.VidraResource{}
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(vidraRes), updated)).To(Succeed())
+		Expect(updated.Status.DeployState).To(Equal(infrahubv1alpha1.StateFailed))
+		Expect(updated.Status.ObservedGeneration).To(Equal(updated.Generation))
+
+		pruned := meta.FindStatusCondition(updated.Status.Conditions, infrahubv1alpha1.ConditionPruned)
+		Expect(pruned).NotTo(BeNil())
+		Expect(pruned.Status).To(Equal(metav1.ConditionFalse))
+		Expect(pruned.Reason).To(Equal(infrahubv1alpha1.ReasonPruneFailed))
+
+		ready := meta.FindStatusCondition(updated.Status.Conditions, infrahubv1alpha1.ConditionReady)
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal(infrahubv1alpha1.ReasonPruneFailed))
+		Expect(ready.Message).To(Equal("delete failed"))
+	})
+
+	It("should use the generic reason if the error carries no condition", func() {
+		Expect(MarkStateFailed(ctx, k8sClient, vidraRes, fmt.Errorf("boom"))).To(MatchError("boom"))
+
+		ready := meta.FindStatusCondition(vidraRes.Status.Conditions, infrahubv1alpha1.ConditionReady)
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Reason).To(Equal(infrahubv1alpha1.ReasonFailed))
+	})
+
+	It("should only mark Ready as Unknown while a new generation is reconciled", func() {
+		MarkReconciling(vidraRes)
+		ready := meta.FindStatusCondition(vidraRes.Status.Conditions, infrahubv1alpha1.ConditionReady)
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Status).To(Equal(metav1.ConditionUnknown))
+		Expect(ready.Reason).To(Equal(infrahubv1alpha1.ReasonReconciling))
+
+		SetCondition(vidraRes, infrahubv1alpha1.ConditionReady, metav1.ConditionTrue, infrahubv1alpha1.ReasonSucceeded, "done")
+		MarkObserved(vidraRes)
+		MarkReconciling(vidraRes)
+		Expect(meta.IsStatusConditionTrue(vidraRes.Status.Conditions, infrahubv1alpha1.ConditionReady)).To(BeTrue())
+	})
+})
+
+var _ = Describe("MarkSuspended", func() {
+	It("should only add the Suspended condition once the resource was suspended", func() {
+		res := &infrahubv1alpha1.InfrahubSync{}
+		MarkSuspended(res, false)
+		Expect(res.Status.Conditions).To(BeEmpty())
+
+		MarkSuspended(res, true)
+		Expect(meta.IsStatusConditionTrue(res.Status.Conditions, infrahubv1alpha1.ConditionSuspended)).To(BeTrue())
+
+		MarkSuspended(res, false)
+		suspended := meta.FindStatusCondition(res.Status.Conditions, infrahubv1alpha1.ConditionSuspended)
+		Expect(suspended.Status).To(Equal(metav1.ConditionFalse))
+		Expect(suspended.Reason).To(Equal(infrahubv1alpha1.ReasonResumed))
+	})
+})
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// kindOrder defines the order in which kinds of the same sync wave are applied, unknown kinds are applied last.
+// Resources are deleted in the reverse order.
+var kindOrder = map[string]int{
+	"Namespace":                      0,
+	"NetworkPolicy":                  1,
+	"ResourceQuota":                  1,
+	"LimitRange":                     1,
+	"CustomResourceDefinition":       2,
+	"ServiceAccount":                 3,
+	"ClusterRole":                    4,
+	"ClusterRoleBinding":             5,
+	"Role":                           4,
+	"RoleBinding":                    5,
+	"Secret":                         6,
+	"ConfigMap":                      6,
+	"StorageClass":                   7,
+	"PersistentVolume":               7,
+	"PersistentVolumeClaim":          8,
+	"Service":                        9,
+	"DaemonSet":                      10,
+	"Pod":                            10,
+	"ReplicaSet":                     10,
+	"Deployment":                     10,
+	"StatefulSet":                    10,
+	"Job":                            11,
+	"CronJob":                        11,
+	"Ingress":                        12,
+	"HorizontalPodAutoscaler":        12,
+	"PodDisruptionBudget":            12,
+	"APIService":                     13,
+	"MutatingWebhookConfiguration":   13,
+	"ValidatingWebhookConfiguration": 13,
+}
+
+func kindRank(kind string) int {
+	if rank, ok := kindOrder[kind]; ok {
+		return rank
+	}
+	return len(kindOrder)
+}
+
+// syncWave returns the sync wave of the resource defined by its sync-wave annotation (default: 0).
+func syncWave(obj *unstructured.Unstructured) (int, error) {
+	value, ok := obj.GetAnnotations()[SyncWaveAnnotation]
+	if !ok || value == "" {
+		return 0, nil
+	}
+	wave, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s annotation %q on %s %s: %w", SyncWaveAnnotation, value, obj.GetKind(), obj.GetName(), err)
+	}
+	return wave, nil
+}
+
+// groupBySyncWave sorts the resources by sync wave and kind and groups them into their waves in ascending order.
+// The order of the manifest is kept for resources of the same wave and kind.
+func groupBySyncWave(objs []*unstructured.Unstructured) ([][]*unstructured.Unstructured, []int, error) {
+	waveOf := make(map[*unstructured.Unstructured]int, len(objs))
+	for _, obj := range objs {
+		wave, err := syncWave(obj)
+		if err != nil {
+			return nil, nil, err
+		}
+		waveOf[obj] = wave
+	}
+
+	sort.SliceStable(objs, func(i, j int) bool {
+		if waveOf[objs[i]] != waveOf[objs[j]] {
+			return waveOf[objs[i]] < waveOf[objs[j]]
+		}
+		return kindRank(objs[i].GetKind()) < kindRank(objs[j].GetKind())
+	})
+
+	var groups [][]*unstructured.Unstructured
+	var waves []int
+	for i, obj := range objs {
+		if i == 0 || waveOf[obj] != waveOf[objs[i-1]] {
+			groups = append(groups, nil)
+			waves = append(waves, waveOf[obj])
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], obj)
+	}
+	return groups, waves, nil
+}
+
+// sortForDeletion sorts managed resources in the reverse apply order, the highest sync wave is deleted first.
+func sortForDeletion(resources []infrahubv1alpha1.ManagedResourceStatus) []infrahubv1alpha1.ManagedResourceStatus {
+	sorted := append([]infrahubv1alpha1.ManagedResourceStatus{}, resources...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].SyncWave != sorted[j].SyncWave {
+			return sorted[i].SyncWave > sorted[j].SyncWave
+		}
+		return kindRank(sorted[i].Kind) > kindRank(sorted[j].Kind)
+	})
+	return sorted
+}
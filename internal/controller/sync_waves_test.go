@@ -0,0 +1,76 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+)
+
+var _ = Describe("Sync waves", func() {
+	newObject := func(kind, name, wave string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetKind(kind)
+		u.SetName(name)
+		if wave != "" {
+			u.SetAnnotations(map[string]string{SyncWaveAnnotation: wave})
+		}
+		return u
+	}
+	names := func(objs []*unstructured.Unstructured) []string {
+		var result []string
+		for _, obj := range objs {
+			result = append(result, obj.GetName())
+		}
+		return result
+	}
+
+	It("should order resources of the same wave by kind and keep the manifest order otherwise", func() {
+		groups, waves, err := groupBySyncWave([]*unstructured.Unstructured{
+			newObject("Deployment", "app", ""),
+			newObject("ConfigMap", "config-b", ""),
+			newObject("Certificate", "cert", ""),
+			newObject("ConfigMap", "config-a", ""),
+			newObject("Namespace", "ns", ""),
+			newObject("CustomResourceDefinition", "crd", ""),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(waves).To(Equal([]int{0}))
+		Expect(names(groups[0])).To(Equal([]string{"ns", "crd", "config-b", "config-a", "app", "cert"}))
+	})
+
+	It("should group resources by their sync wave annotation", func() {
+		groups, waves, err := groupBySyncWave([]*unstructured.Unstructured{
+			newObject("ConfigMap", "late", "5"),
+			newObject("Deployment", "default", ""),
+			newObject("Namespace", "early", "-1"),
+			newObject("Service", "also-late", "5"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(waves).To(Equal([]int{-1, 0, 5}))
+		Expect(names(groups[0])).To(Equal([]string{"early"}))
+		Expect(names(groups[1])).To(Equal([]string{"default"}))
+		Expect(names(groups[2])).To(Equal([]string{"late", "also-late"}))
+	})
+
+	It("should reject an invalid sync wave annotation", func() {
+		_, _, err := groupBySyncWave([]*unstructured.Unstructured{newObject("ConfigMap", "invalid", "first")})
+		Expect(err).To(MatchError(ContainSubstring("invalid vidra.infrahub.operators.com/sync-wave annotation")))
+	})
+
+	It("should delete resources in the reverse order", func() {
+		sorted := sortForDeletion([]infrahubv1alpha1.ManagedResourceStatus{
+			{Kind: "Namespace", Name: "ns"},
+			{Kind: "ConfigMap", Name: "late", SyncWave: 2},
+			{Kind: "Deployment", Name: "app"},
+			{Kind: "ConfigMap", Name: "config"},
+		})
+		var order []string
+		for _, mr := range sorted {
+			order = append(order, mr.Name)
+		}
+		Expect(order).To(Equal([]string{"late", "app", "config", "ns"}))
+	})
+})
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	"github.com/infrahub-operator/vidra/internal/adapter/k8s"
+	"github.com/infrahub-operator/vidra/internal/domain"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultClusterProbeInterval is how often the connection to a VidraCluster is checked
+const DefaultClusterProbeInterval = 5 * time.Minute
+
+// VidraClusterReconciler checks the connection to the VidraClusters and reports their Kubernetes version
+type VidraClusterReconciler struct {
+	client.Client
+	Scheme                     *runtime.Scheme
+	DynamicMulticlusterFactory domain.DynamicMulticlusterFactory
+	ProbeInterval              time.Duration
+}
+
+// +kubebuilder:rbac:groups=infrahub.operators.com,resources=vidraclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrahub.operators.com,resources=vidraclusters/status,verbs=get;update;patch
+
+func (r *VidraClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	cluster := &infrahubv1alpha1.VidraCluster{}
+	if err := r.Get(ctx, req.NamespacedName, cluster); err != nil {
+		if errors.IsNotFound(err) {
+			r.DynamicMulticlusterFactory.EvictCluster(req.Name)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	version, err := r.DynamicMulticlusterFactory.ServerVersion(ctx, cluster, r.Client)
+	if err != nil {
+		logger.Info("VidraCluster is not reachable", "cluster", cluster.Name, "error", err.Error())
+	}
+
+	if err := MarkState(ctx, r.Client, cluster, func() {
+		cluster.Status.LastProbeTime = metav1.Now()
+		if err != nil {
+			reason := infrahubv1alpha1.ReasonConnectionFailed
+			if errors.IsUnauthorized(err) {
+				reason = infrahubv1alpha1.ReasonUnauthorized
+			}
+			cluster.Status.KubernetesVersion = ""
+			SetCondition(cluster, infrahubv1alpha1.ConditionConnected, metav1.ConditionFalse, reason, err.Error())
+			SetCondition(cluster, infrahubv1alpha1.ConditionVersionDetected, metav1.ConditionFalse,
+				infrahubv1alpha1.ReasonVersionUnknown, "The cluster is not reachable")
+		} else {
+			cluster.Status.KubernetesVersion = version
+			SetCondition(cluster, infrahubv1alpha1.ConditionConnected, metav1.ConditionTrue,
+				infrahubv1alpha1.ReasonSucceeded, "The API server of the cluster is reachable")
+			SetCondition(cluster, infrahubv1alpha1.ConditionVersionDetected, metav1.ConditionTrue,
+				infrahubv1alpha1.ReasonSucceeded, "Kubernetes "+version)
+		}
+		MarkObserved(cluster)
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{RequeueAfter: r.probeInterval()}, nil
+}
+
+// probeInterval returns how often the connection to the clusters is checked
+func (r *VidraClusterReconciler) probeInterval() time.Duration {
+	if r.ProbeInterval <= 0 {
+		return DefaultClusterProbeInterval
+	}
+	return r.ProbeInterval
+}
+
+// clusterReferencesIndex indexes the VidraClusters by the Secrets and ConfigMaps holding their credentials or CA bundle
+const clusterReferencesIndex = ".spec.references"
+
+// clustersReferencing maps a changed Secret or ConfigMap to the VidraClusters reading their credentials or CA bundle
+// from it, so rotated credentials are probed right away
+func (r *VidraClusterReconciler) clustersReferencing(kind string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		var clusters infrahubv1alpha1.VidraClusterList
+		if err := r.List(ctx, &clusters, client.MatchingFields{
+			clusterReferencesIndex: referenceKey(kind, obj.GetNamespace(), obj.GetName()),
+		}); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to list VidraClusters")
+			return nil
+		}
+		requests := make([]reconcile.Request, 0, len(clusters.Items))
+		for _, cluster := range clusters.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: cluster.Name}})
+		}
+		return requests
+	}
+}
+
+// clusterReferences returns the index keys of the objects the VidraCluster reads its credentials or CA bundle from
+func clusterReferences(obj client.Object) []string {
+	cluster, ok := obj.(*infrahubv1alpha1.VidraCluster)
+	if !ok {
+		return nil
+	}
+	var keys []string
+	if ref := cluster.Spec.CredentialsSecretRef; ref != nil {
+		keys = append(keys, referenceKey("Secret", ref.Namespace, ref.Name))
+	}
+	if ca := cluster.Spec.CABundle; ca != nil {
+		kind := ca.Kind
+		if kind == "" {
+			kind = "ConfigMap"
+		}
+		keys = append(keys, referenceKey(kind, ca.Namespace, ca.Name))
+	}
+	return keys
+}
+
+// referenceKey returns the index key of the object of the kind
+func referenceKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+func (r *VidraClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.DynamicMulticlusterFactory == nil {
+		r.DynamicMulticlusterFactory = k8s.NewDynamicMulticlusterFactory()
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &infrahubv1alpha1.VidraCluster{},
+		clusterReferencesIndex, clusterReferences); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&infrahubv1alpha1.VidraCluster{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.clustersReferencing("Secret"))).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.clustersReferencing("ConfigMap"))).
+		Complete(r)
+}
@@ -0,0 +1,179 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	mock "github.com/infrahub-operator/vidra/internal/mocks"
+)
+
+var _ = Describe("VidraCluster", func() {
+	var (
+		ctx        = context.Background()
+		fakeClient client.Client
+		factory    *mock.MockDynamicMulticlusterFactory
+	)
+
+	newCluster := func(name string, labels map[string]string) *infrahubv1alpha1.VidraCluster {
+		return &infrahubv1alpha1.VidraCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Generation: 1},
+			Spec: infrahubv1alpha1.VidraClusterSpec{
+				Server:               "https://" + name + ".example.com:6443",
+				CredentialsSecretRef: &infrahubv1alpha1.ClusterCredentialsReference{Name: name, Namespace: "vidra-system"},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(infrahubv1alpha1.AddToScheme(scheme)).To(Succeed())
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).
+			WithStatusSubresource(&infrahubv1alpha1.VidraCluster{}).
+			WithIndex(&infrahubv1alpha1.VidraCluster{}, clusterReferencesIndex, clusterReferences).
+			WithObjects(
+				newCluster("lab-1", map[string]string{"environment": "lab", "site": "zrh"}),
+				newCluster("lab-2", map[string]string{"environment": "lab", "site": "ber"}),
+			).Build()
+		factory = mock.NewMockDynamicMulticlusterFactory(gomock.NewController(GinkgoT()))
+	})
+
+	Context("resolving the destination cluster", func() {
+		It("should get the cluster by name", func() {
+			cluster, err := resolveCluster(ctx, fakeClient, infrahubv1alpha1.InfrahubSyncDestination{Cluster: "lab-2"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cluster.Name).To(Equal("lab-2"))
+
+			_, err = resolveCluster(ctx, fakeClient, infrahubv1alpha1.InfrahubSyncDestination{Cluster: "missing"})
+			Expect(err).To(MatchError(ContainSubstring("failed to get VidraCluster missing")))
+		})
+
+		It("should select exactly one cluster by labels", func() {
+			cluster, err := resolveCluster(ctx, fakeClient, infrahubv1alpha1.InfrahubSyncDestination{
+				ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"site": "zrh"}},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cluster.Name).To(Equal("lab-1"))
+
+			_, err = resolveCluster(ctx, fakeClient, infrahubv1alpha1.InfrahubSyncDestination{
+				ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"environment": "lab"}},
+			})
+			Expect(err).To(MatchError(ContainSubstring("matches more than one VidraCluster: lab-1, lab-2")))
+
+			_, err = resolveCluster(ctx, fakeClient, infrahubv1alpha1.InfrahubSyncDestination{
+				ClusterSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"environment": "prod"}},
+			})
+			Expect(err).To(MatchError(ContainSubstring("no VidraCluster matches the cluster selector environment=prod")))
+		})
+
+		It("should map with the REST mapper of the destination cluster", func() {
+			local := meta.NewDefaultRESTMapper(nil)
+			remote := meta.NewDefaultRESTMapper(nil)
+			remoteClient := fake.NewClientBuilder().WithRESTMapper(remote).Build()
+			reconciler := &VidraResourceReconciler{Client: fakeClient, RESTMapper: local}
+
+			Expect(reconciler.restMapperFor(fakeClient)).To(BeIdenticalTo(local))
+			Expect(reconciler.restMapperFor(remoteClient)).To(BeIdenticalTo(remote))
+		})
+
+		It("should return no cluster without a reference", func() {
+			cluster, err := resolveCluster(ctx, fakeClient, infrahubv1alpha1.InfrahubSyncDestination{Server: "https://remote:6443"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cluster).To(BeNil())
+		})
+	})
+
+	Context("probing the connection", func() {
+		reconcile := func() *infrahubv1alpha1.VidraCluster {
+			reconciler := &VidraClusterReconciler{Client: fakeClient, DynamicMulticlusterFactory: factory}
+			result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "lab-1"}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(DefaultClusterProbeInterval))
+
+			cluster := &infrahubv1alpha1.VidraCluster{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "lab-1"}, cluster)).To(Succeed())
+			return cluster
+		}
+
+		It("should report the version of a reachable cluster", func() {
+			factory.EXPECT().ServerVersion(gomock.Any(), gomock.Any(), fakeClient).Return("v1.32.1", nil)
+
+			cluster := reconcile()
+			Expect(cluster.Status.KubernetesVersion).To(Equal("v1.32.1"))
+			Expect(cluster.Status.ObservedGeneration).To(Equal(int64(1)))
+			Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, infrahubv1alpha1.ConditionConnected)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, infrahubv1alpha1.ConditionVersionDetected)).To(BeTrue())
+		})
+
+		It("should report an unreachable cluster", func() {
+			factory.EXPECT().ServerVersion(gomock.Any(), gomock.Any(), fakeClient).Return("v1.32.1", nil)
+			reconcile()
+			factory.EXPECT().ServerVersion(gomock.Any(), gomock.Any(), fakeClient).Return("", errors.New("connection refused"))
+
+			cluster := reconcile()
+			Expect(cluster.Status.KubernetesVersion).To(BeEmpty())
+			connected := meta.FindStatusCondition(cluster.Status.Conditions, infrahubv1alpha1.ConditionConnected)
+			Expect(connected.Status).To(Equal(metav1.ConditionFalse))
+			Expect(connected.Reason).To(Equal(infrahubv1alpha1.ReasonConnectionFailed))
+			Expect(connected.Message).To(Equal("connection refused"))
+			Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions, infrahubv1alpha1.ConditionVersionDetected)).To(BeTrue())
+		})
+
+		It("should report rejected credentials as unauthorized", func() {
+			factory.EXPECT().ServerVersion(gomock.Any(), gomock.Any(), fakeClient).
+				Return("", fmt.Errorf("failed to get the version of cluster lab-1: %w", apierrors.NewUnauthorized("token expired")))
+
+			cluster := reconcile()
+			connected := meta.FindStatusCondition(cluster.Status.Conditions, infrahubv1alpha1.ConditionConnected)
+			Expect(connected.Reason).To(Equal(infrahubv1alpha1.ReasonUnauthorized))
+		})
+
+		It("should drop the client of a deleted cluster", func() {
+			factory.EXPECT().EvictCluster("deleted")
+			reconciler := &VidraClusterReconciler{Client: fakeClient, DynamicMulticlusterFactory: factory}
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "deleted"}})
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	It("should probe the clusters reading their credentials or CA bundle from a changed object", func() {
+		cluster := newCluster("lab-1", nil)
+		cluster.Spec.CABundle = &infrahubv1alpha1.CABundleSource{Name: "lab-ca", Namespace: "vidra-system"}
+
+		Expect(clusterReferences(cluster)).To(ConsistOf("Secret/vidra-system/lab-1", "ConfigMap/vidra-system/lab-ca"))
+
+		By("reading the CA bundle of a cluster from a Secret")
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "lab-1"}, cluster)).To(Succeed())
+		cluster.Spec.CABundle = &infrahubv1alpha1.CABundleSource{Kind: "Secret", Name: "lab-ca", Namespace: "vidra-system"}
+		Expect(clusterReferences(cluster)).To(ConsistOf("Secret/vidra-system/lab-1", "Secret/vidra-system/lab-ca"))
+		Expect(fakeClient.Update(ctx, cluster)).To(Succeed())
+
+		reconciler := &VidraClusterReconciler{Client: fakeClient}
+		requests := reconciler.clustersReferencing("Secret")(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "lab-2", Namespace: "vidra-system"}})
+		Expect(requests).To(ConsistOf(reconcile.Request{NamespacedName: types.NamespacedName{Name: "lab-2"}}))
+		requests = reconciler.clustersReferencing("Secret")(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "lab-ca", Namespace: "vidra-system"}})
+		Expect(requests).To(ConsistOf(reconcile.Request{NamespacedName: types.NamespacedName{Name: "lab-1"}}))
+		requests = reconciler.clustersReferencing("ConfigMap")(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "lab-ca", Namespace: "vidra-system"}})
+		Expect(requests).To(BeEmpty())
+		requests = reconciler.clustersReferencing("Secret")(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "lab-1", Namespace: "default"}})
+		Expect(requests).To(BeEmpty())
+	})
+})
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 	"time"
 
 	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
@@ -29,6 +30,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
@@ -36,7 +38,11 @@ import (
 const (
 	FinalizerName   = "vidraresource.infrahub.operators.com/finalizer"
 	OwnerAnnotation = "vidraresource.infrahub.operators.com/owned-by"
-	vidraOperator   = "vidra"
+	// SyncWaveAnnotation orders the apply of manifest documents, lower waves are applied and healthy first
+	SyncWaveAnnotation = "vidra.infrahub.operators.com/sync-wave"
+	// FieldManager prefixes the field managers used for server-side apply of managed resources
+	FieldManager  = "vidra"
+	vidraOperator = "vidra"
 )
 
 type VidraResourceReconciler struct {
@@ -47,14 +53,20 @@ type VidraResourceReconciler struct {
 	DynamicMulticlusterFactory domain.DynamicMulticlusterFactory
 	DynamicWatcherFactory      domain.DynamicWatcherFactory
 	DynamicWatcherClient       dynamic.Interface
+	HealthChecker              domain.HealthChecker
 	RequeueAfter               time.Duration
 	EventBasedReconcile        bool
+
+	watchMu sync.Mutex
+	// watchedClusters holds the remote cluster watched for each VidraResource by its name
+	watchedClusters map[string]string
 }
 
 // +kubebuilder:rbac:groups=infrahub.operators.com,resources=infrahubresources,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrahub.operators.com,resources=infrahubresources/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=infrahub.operators.com,resources=infrahubresources/finalizers,verbs=update
 // +kubebuilder:rbac:groups="*",resources="*",verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrahub.operators.com,resources=vidraclusters,verbs=get;list;watch
 
 func (r *VidraResourceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
@@ -64,31 +76,37 @@ func (r *VidraResourceReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	if err := r.Get(ctx, req.NamespacedName, res); err != nil {
 		if errors.IsNotFound(err) {
 			logger.Info("VidraResource resource not found, skipping")
+			r.unwatchCluster(req.Name)
 			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "Failed to get VidraResource resource")
 		return ctrl.Result{}, MarkStateFailed(ctx, r.Client, res, err)
 	}
-	var destClient client.Client
-	if res.Spec.Destination.Server == "" || res.Spec.Destination.Server == "https://kubernetes.default.svc" {
-		logger.Info("Using local client for destination")
 
-		destClient = r.Client
-	} else {
-		logger.Info("Using cached client for destination", "server", res.Spec.Destination.Server)
-		var err error
-		destClient, err = r.DynamicMulticlusterFactory.GetCachedClientFor(ctx, res.Spec.Destination.Server, r.Client)
-		if err != nil {
-			return ctrl.Result{}, MarkStateFailed(ctx, r.Client, res, fmt.Errorf("failed to get client for destination: %w", err))
-		}
+	if res.Spec.Suspend && res.DeletionTimestamp.IsZero() {
+		logger.Info("VidraResource is suspended, skipping apply and prune")
+		return ctrl.Result{}, MarkState(ctx, r.Client, res, func() {
+			MarkSuspended(res, true)
+			MarkObserved(res)
+		})
+	}
+
+	destClient, destCluster, err := r.destinationClient(ctx, res)
+	if err != nil {
+		return ctrl.Result{}, MarkStateFailed(ctx, r.Client, res, NewConditionError(
+			infrahubv1alpha1.ConditionApplied, infrahubv1alpha1.ReasonDestinationUnavailable,
+			fmt.Errorf("failed to get client for destination: %w", err)))
 	}
 
 	if !res.DeletionTimestamp.IsZero() {
+		r.unwatchCluster(res.Name)
 		return r.handleDeletion(ctx, res, destClient)
 	}
 
 	if err := MarkState(ctx, r.Client, res, func() {
 		res.Status.DeployState = infrahubv1alpha1.StateRunning
+		MarkReconciling(res)
+		MarkSuspended(res, false)
 	}); err != nil {
 		return ctrl.Result{}, err
 	}
@@ -100,19 +118,42 @@ func (r *VidraResourceReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		}
 	}
 
-	if res.Spec.Manifest == "" {
+	manifest, err := loadManifest(ctx, r.Client, res)
+	if err != nil {
+		logger.Error(err, "Failed to load the manifest chunks")
+		return ctrl.Result{}, MarkStateFailed(ctx, r.Client, res, NewConditionError(
+			infrahubv1alpha1.ConditionApplied, infrahubv1alpha1.ReasonInvalidManifest, err))
+	}
+	if manifest == "" {
 		logger.Error(nil, "No manifests available in spec to reconcile")
-		return ctrl.Result{}, MarkStateFailed(ctx, r.Client, res, fmt.Errorf("no manifests available in spec to reconcile"))
+		return ctrl.Result{}, MarkStateFailed(ctx, r.Client, res, NewConditionError(
+			infrahubv1alpha1.ConditionApplied, infrahubv1alpha1.ReasonInvalidManifest,
+			fmt.Errorf("no manifests available in spec to reconcile")))
 	}
-	contentReader := strings.NewReader(res.Spec.Manifest)
+	contentReader := strings.NewReader(manifest)
 
-	newResources, gvrList, err := r.decodeAndApplyResources(ctx, res, contentReader, destClient)
+	result, err := r.decodeAndApplyResources(ctx, res, contentReader, destClient)
 	if err != nil {
 		logger.Error(err, "Failed to decode and apply resources")
-		return ctrl.Result{}, MarkStateFailed(ctx, r.Client, res, err)
+		return ctrl.Result{}, MarkStateFailed(ctx, r.Client, res,
+			NewConditionError(infrahubv1alpha1.ConditionApplied, infrahubv1alpha1.ReasonApplyFailed, err))
+	}
+
+	if result.pendingWave != "" {
+		// Later waves are not applied yet, so nothing is pruned until all waves are through
+		if err := MarkState(ctx, r.Client, res, func() {
+			res.Status.ManagedResources = mergeResourceLists(res.Status.ManagedResources, result.resources)
+			SetCondition(res, infrahubv1alpha1.ConditionApplied, metav1.ConditionFalse, infrahubv1alpha1.ReasonWaitingForSyncWave, result.pendingWave)
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+		logger.Info("Waiting for sync wave", "message", result.pendingWave)
+		return ctrl.Result{RequeueAfter: healthRequeueInterval}, nil
 	}
 
-	if err := r.cleanupRemovedResources(ctx, res, newResources, destClient); err != nil {
+	outcome, err := r.cleanupRemovedResources(ctx, res, result.resources, destClient)
+	if err != nil {
+		err = NewConditionError(infrahubv1alpha1.ConditionPruned, infrahubv1alpha1.ReasonPruneFailed, err)
 		logger.Error(err, "Failed to clean up removed resources")
 		if res.Status.DeployState == infrahubv1alpha1.StateStale {
 			logger.Error(err, "State is stale, returning error")
@@ -121,7 +162,7 @@ func (r *VidraResourceReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, MarkStateFailed(ctx, r.Client, res, err)
 	}
 
-	res.Status.ManagedResources = buildFinalResourceList(res.Status.ManagedResources, newResources)
+	res.Status.ManagedResources = mergeResourceLists(res.Status.ManagedResources, result.resources)
 	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		return r.Status().Update(ctx, res)
 	}); err != nil {
@@ -129,20 +170,37 @@ func (r *VidraResourceReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	if r.EventBasedReconcile || res.Spec.Destination.ReconcileOnEvents {
-		r.DynamicWatcherFactory.StartWatchingGVRs(
-			r.DynamicWatcherClient,
-			gvrList,
-			func(obj *unstructured.Unstructured, gvr schema.GroupVersionResource) {
-				r.handleLabeledResource(obj, gvr)
-				r.triggerReconcileForOwner(obj)
-			},
-		)
-		r.RequeueAfter = 0 // Disable default requeue for event-based reconciliation
+		if destCluster == "" {
+			r.unwatchCluster(res.Name)
+			r.DynamicWatcherFactory.StartWatchingGVRs(
+				r.DynamicWatcherClient,
+				result.gvrList,
+				func(obj *unstructured.Unstructured, gvr schema.GroupVersionResource) {
+					r.handleLabeledResource(obj, gvr)
+					r.triggerReconcileForOwner(obj)
+				},
+			)
+			r.RequeueAfter = 0 // Disable default requeue for event-based reconciliation
+		} else if err := r.watchCluster(res.Name, destCluster, destClient, result.gvrList); err != nil {
+			logger.Error(err, "Failed to watch the resources on the destination cluster", "cluster", destCluster)
+		} else {
+			r.RequeueAfter = 0 // Disable default requeue for event-based reconciliation
+		}
+	} else {
+		r.unwatchCluster(res.Name)
 	}
 
+	managedResources, health, healthMessage := r.assessHealth(ctx, res.Status.ManagedResources, destClient)
+
 	if err := MarkState(ctx, r.Client, res, func() {
 		res.Status.LastSyncTime = metav1.Now()
-		res.Status.DeployState = infrahubv1alpha1.StateSucceeded
+		res.Status.ManagedResources = managedResources
+		res.Status.Health = health
+		res.Status.Drifted = result.drifted
+		setHealthCondition(res, health, healthMessage)
+		SetCondition(res, infrahubv1alpha1.ConditionApplied, metav1.ConditionTrue, infrahubv1alpha1.ReasonSucceeded, "All manifests applied")
+		res.Status.DeployState = setPruneConditions(res, outcome)
+		MarkObserved(res)
 		if !strings.Contains(res.Status.LastError, "Warning:") {
 			res.Status.LastError = ""
 		}
@@ -150,7 +208,15 @@ func (r *VidraResourceReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
-	logger.Info("Reconciliation complete")
+	if err := r.updateOwnerHealth(ctx, res); err != nil {
+		logger.Error(err, "Failed to roll up health to InfrahubSync")
+	}
+
+	logger.Info("Reconciliation complete", "health", health)
+	if health != infrahubv1alpha1.HealthHealthy && (r.RequeueAfter == 0 || r.RequeueAfter > healthRequeueInterval) {
+		// Keep the health of resources which are still progressing up to date
+		return ctrl.Result{RequeueAfter: healthRequeueInterval}, nil
+	}
 	return ctrl.Result{RequeueAfter: r.RequeueAfter}, nil
 }
 
@@ -161,29 +227,42 @@ func (r *VidraResourceReconciler) handleDeletion(ctx context.Context, res *infra
 	}
 	logger.Info("Cleaning up managed resources")
 
-	for _, mr := range res.Status.ManagedResources {
+	for _, mr := range sortForDeletion(res.Status.ManagedResources) {
 		if err := r.deleteManagedResource(ctx, res, mr, destClient); err != nil {
-			return ctrl.Result{}, MarkStateFailed(ctx, r.Client, res, err)
+			return ctrl.Result{}, MarkStateFailed(ctx, r.Client, res,
+				NewConditionError(infrahubv1alpha1.ConditionPruned, infrahubv1alpha1.ReasonPruneFailed, err))
 		}
 	}
 	return ctrl.Result{}, r.removeFinalizer(ctx, res)
 }
 
+// applyResult contains the outcome of applying the documents of a manifest
+type applyResult struct {
+	// resources which were applied, by resource key
+	resources map[string]infrahubv1alpha1.ManagedResourceStatus
+	// gvrList contains the GVRs to watch for event-based reconciliation
+	gvrList []schema.GroupVersionResource
+	// pendingWave describes the sync wave which is not healthy yet, empty if all waves were applied
+	pendingWave string
+	// drifted resources which were detected but not corrected
+	drifted []infrahubv1alpha1.DriftedResource
+}
+
+// decodeAndApplyResources decodes all documents of the manifest and applies them ordered by sync wave and kind.
+// Each sync wave has to become healthy before the next one is applied. If a wave is not healthy yet,
+// the resources applied so far are returned together with a message describing the pending wave.
 func (r *VidraResourceReconciler) decodeAndApplyResources(
 	ctx context.Context,
 	res *infrahubv1alpha1.VidraResource,
 	contentReader io.Reader,
 	destClient client.Client,
-) (map[string]infrahubv1alpha1.ManagedResourceStatus, []schema.GroupVersionResource, error) {
+) (*applyResult, error) {
 	logger := log.FromContext(ctx).WithValues("resource", res.Name)
 
 	reader := bufio.NewReaderSize(contentReader, 4096)
 	decoder := yaml.NewYAMLOrJSONDecoder(reader, 4096)
 
-	resources := map[string]infrahubv1alpha1.ManagedResourceStatus{}
-	gvrList := []schema.GroupVersionResource{}
-	seenGVR := map[schema.GroupVersionResource]struct{}{}
-
+	var objs []*unstructured.Unstructured
 	for {
 		u := &unstructured.Unstructured{}
 		if err := decoder.Decode(u); err != nil {
@@ -191,77 +270,144 @@ func (r *VidraResourceReconciler) decodeAndApplyResources(
 				break
 			}
 			logger.Error(err, "Failed to decode")
-			return nil, nil, fmt.Errorf("decode artifact: %w", err)
+			return nil, NewConditionError(infrahubv1alpha1.ConditionApplied, infrahubv1alpha1.ReasonInvalidManifest,
+				fmt.Errorf("decode artifact: %w", err))
 		}
+		objs = append(objs, u)
+	}
 
-		gvk := u.GroupVersionKind()
-		mapping, err := r.RESTMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
-		if err != nil {
-			return nil, nil, fmt.Errorf("REST mapping: %w", err)
-		}
+	groups, waves, err := groupBySyncWave(objs)
+	if err != nil {
+		return nil, NewConditionError(infrahubv1alpha1.ConditionApplied, infrahubv1alpha1.ReasonInvalidManifest, err)
+	}
 
-		// Collect GVRs for dynamic watcher
-		if (r.EventBasedReconcile || res.Spec.Destination.ReconcileOnEvents) && destClient == r.Client {
-			gvr := mapping.Resource
-			if _, exists := seenGVR[gvr]; !exists {
-				gvrList = append(gvrList, gvr)
-				seenGVR[gvr] = struct{}{}
+	result := &applyResult{
+		resources: map[string]infrahubv1alpha1.ManagedResourceStatus{},
+		gvrList:   []schema.GroupVersionResource{},
+	}
+	seenGVR := map[schema.GroupVersionResource]struct{}{}
+	lastApplied := map[string]string{}
+	for _, mr := range res.Status.ManagedResources {
+		lastApplied[resourceKey(mr)] = mr.LastAppliedHash
+	}
+
+	mapper := r.restMapperFor(destClient)
+	for i, group := range groups {
+		waveResources := make([]infrahubv1alpha1.ManagedResourceStatus, 0, len(group))
+		for _, u := range group {
+			gvk := u.GroupVersionKind()
+			// Map at apply time, CRDs of earlier waves are known by now
+			mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+			if err != nil {
+				return nil, fmt.Errorf("REST mapping: %w", err)
 			}
-		}
 
-		if mapping.Scope.Name() == meta.RESTScopeNameNamespace && u.GetNamespace() == "" {
-			u.SetNamespace(res.Spec.Destination.Namespace)
-		}
-		if destClient == r.Client {
-			if err := ctrl.SetControllerReference(res, u, r.Scheme); err != nil {
-				return nil, nil, fmt.Errorf("set controller reference: %w", err)
+			// Collect GVRs for dynamic watcher
+			if r.EventBasedReconcile || res.Spec.Destination.ReconcileOnEvents {
+				gvr := mapping.Resource
+				if _, exists := seenGVR[gvr]; !exists {
+					result.gvrList = append(result.gvrList, gvr)
+					seenGVR[gvr] = struct{}{}
+				}
 			}
-		}
 
-		annotateWithOwner(u, res.Name)
-		if err := r.applyResource(ctx, res, u, destClient); err != nil {
-			logger.Error(err, "apply resource failed", "GVK", gvk, "Name", u.GetName())
-			return nil, nil, err
+			if mapping.Scope.Name() == meta.RESTScopeNameNamespace && u.GetNamespace() == "" {
+				u.SetNamespace(res.Spec.Destination.Namespace)
+			}
+			// Fields owned by other controllers are not compared, applyResource applies them with their live value
+			if err := removeIgnoredDifferences(u, res.Spec.Destination.IgnoreDifferences); err != nil {
+				return nil, err
+			}
+			if destClient == r.Client {
+				if err := ctrl.SetControllerReference(res, u, r.Scheme); err != nil {
+					return nil, fmt.Errorf("set controller reference: %w", err)
+				}
+			}
+
+			annotateWithOwner(u, res.Name)
+			status := infrahubv1alpha1.ManagedResourceStatus{
+				Kind:       gvk.Kind,
+				APIVersion: gvk.GroupVersion().String(),
+				Name:       u.GetName(),
+				Namespace:  u.GetNamespace(),
+				SyncWave:   waves[i],
+			}
+			hash, err := manifestHash(u)
+			if err != nil {
+				return nil, err
+			}
+			status.LastAppliedHash = hash
+
+			diff, err := r.applyResource(ctx, res, u, destClient, lastApplied[resourceKey(status)] != hash)
+			if err != nil {
+				logger.Error(err, "apply resource failed", "GVK", gvk, "Name", u.GetName())
+				return nil, err
+			}
+			if len(diff) > 0 {
+				result.drifted = append(result.drifted, infrahubv1alpha1.DriftedResource{
+					Kind:       status.Kind,
+					APIVersion: status.APIVersion,
+					Name:       status.Name,
+					Namespace:  status.Namespace,
+					Diff:       diff,
+				})
+			}
+			result.resources[resourceKey(status)] = status
+			waveResources = append(waveResources, status)
 		}
 
-		status := infrahubv1alpha1.ManagedResourceStatus{
-			Kind:       gvk.Kind,
-			APIVersion: gvk.GroupVersion().String(),
-			Name:       u.GetName(),
-			Namespace:  u.GetNamespace(),
+		// The last wave does not block anything, its health is assessed with all other resources
+		if i == len(groups)-1 {
+			break
+		}
+		if _, health, message := r.assessHealth(ctx, waveResources, destClient); health != infrahubv1alpha1.HealthHealthy {
+			logger.Info("Sync wave is not healthy yet", "wave", waves[i], "health", health)
+			result.pendingWave = fmt.Sprintf("waiting for sync wave %d to become healthy: %s", waves[i], message)
+			return result, nil
 		}
-		resources[resourceKey(status)] = status
 	}
 
-	return resources, gvrList, nil
+	return result, nil
 }
 
+// cleanupRemovedResources prunes the managed resources which are no longer part of the manifest according to the prune policy.
+// Stale resources which are kept stay in the managed resources, the outcome describes why they were kept.
 func (r *VidraResourceReconciler) cleanupRemovedResources(
 	ctx context.Context,
 	res *infrahubv1alpha1.VidraResource,
 	current map[string]infrahubv1alpha1.ManagedResourceStatus,
 	destClient client.Client,
-) error {
+) (pruneOutcome, error) {
 	logger := log.FromContext(ctx)
-	var remaining []infrahubv1alpha1.ManagedResourceStatus
+	var remaining, stale []infrahubv1alpha1.ManagedResourceStatus
 
-	for _, old := range res.Status.ManagedResources {
+	for _, old := range sortForDeletion(res.Status.ManagedResources) {
 		key := resourceKey(old)
 		if _, stillExists := current[key]; stillExists {
 			// Still managed — retain it
 			remaining = append(remaining, old)
 			continue
 		}
+		stale = append(stale, old)
+	}
+
+	outcome := checkPrune(res.Spec.Destination, len(stale), len(res.Status.ManagedResources), "resources")
+	if outcome.reason != "" {
+		logger.Info("Keeping stale resources", "reason", outcome.reason, "message", outcome.message)
+		res.Status.ManagedResources = append(remaining, stale...)
+		return outcome, nil
+	}
 
+	for _, old := range stale {
 		// Resource is stale — prepare for deletion
 		if err := r.deleteManagedResource(ctx, res, old, destClient); err != nil {
 			logger.Error(err, "Failed to delete managed resource", "resource", old)
-			return err
+			return outcome, err
 		}
 	}
 
 	res.Status.ManagedResources = remaining
-	return nil
+	return outcome, nil
 }
 
 func (r *VidraResourceReconciler) deleteManagedResource(ctx context.Context, res *infrahubv1alpha1.VidraResource, old infrahubv1alpha1.ManagedResourceStatus, destClient client.Client) error {
@@ -283,13 +429,18 @@ func (r *VidraResourceReconciler) deleteManagedResource(ctx context.Context, res
 		return fmt.Errorf("fetch resource %s: %w", old.Name, err)
 	}
 
-	if obj.GetAnnotations()["managed-by"] != vidraOperator {
+	if !isManagedByVidra(obj) {
 		logger.Info("Skipping deletion, resource not managed by this Infrahub Operator",
 			"expectedOwner", res.Name,
 			"actualAnnotations", obj.GetAnnotations())
 		return nil
 	}
 
+	if prunePolicy(res.Spec.Destination) == infrahubv1alpha1.PrunePolicyOrphan || pruneDisabled(obj) {
+		logger.Info("Orphaning resource instead of deleting it")
+		return releaseManagedResource(ctx, res, obj, destClient)
+	}
+
 	if obj.GetAnnotations()[OwnerAnnotation] != res.Name {
 		owners := obj.GetAnnotations()[OwnerAnnotation]
 		ownerList := removeString(strings.Split(owners, ","), res.Name)
@@ -297,7 +448,7 @@ func (r *VidraResourceReconciler) deleteManagedResource(ctx context.Context, res
 		objAnnotations[OwnerAnnotation] = strings.Join(ownerList, ",")
 		obj.SetAnnotations(objAnnotations)
 		if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-			return destClient.Update(ctx, obj)
+			return destClient.Update(ctx, obj, client.FieldOwner(FieldManager))
 		}); err != nil {
 			return fmt.Errorf("failed to update resource annotations: %w", err)
 		}
@@ -320,7 +471,16 @@ func (r *VidraResourceReconciler) deleteManagedResource(ctx context.Context, res
 	return nil
 }
 
-func (r *VidraResourceReconciler) applyResource(ctx context.Context, res *infrahubv1alpha1.VidraResource, desired *unstructured.Unstructured, destClient client.Client) error {
+// applyResource applies the desired resource if it differs from the existing one.
+// If the manifest did not change since the last apply, the difference is drift and handled according to the drift policy,
+// the returned diff is only set if drift was detected but not corrected.
+func (r *VidraResourceReconciler) applyResource(
+	ctx context.Context,
+	res *infrahubv1alpha1.VidraResource,
+	desired *unstructured.Unstructured,
+	destClient client.Client,
+	manifestChanged bool,
+) ([]string, error) {
 	logger := log.FromContext(ctx)
 
 	// Prepare the existing resource object
@@ -341,29 +501,42 @@ func (r *VidraResourceReconciler) applyResource(ctx context.Context, res *infrah
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Resource doesn't exist, create it
-			return destClient.Create(ctx, desired)
+			return nil, r.serverSideApply(ctx, res, desired, destClient)
 		}
-		return err
+		return nil, err
 	}
 
 	// Log resource existence and check if it's managed by the operator
 	logger.Info("Resource already exists", "name", existing.GetName(), "namespace", existing.GetNamespace())
 
-	if existing.GetAnnotations()["managed-by"] != vidraOperator && res.Status.LastSyncTime.IsZero() {
+	if err := keepIgnoredLiveValues(desired, existing, res.Spec.Destination.IgnoreDifferences); err != nil {
+		return nil, err
+	}
+
+	if !isManagedByVidra(existing) && res.Status.LastSyncTime.IsZero() {
 		fmt.Printf("Resource %s/%s already exists but is not managed by this operator\n", existing.GetNamespace(), existing.GetName())
-		return fmt.Errorf("resource %s/%s already exists but is not managed by this operator", existing.GetNamespace(), existing.GetName())
+		return nil, fmt.Errorf("resource %s/%s already exists but is not managed by this operator", existing.GetNamespace(), existing.GetName())
 	}
 
-	// Check if annotations match, if so, update resource
+	// Check if this vidraResource is one of the owners, if so, apply the resource
 	if r.shouldUpdateResource(existing, desired) {
-		logger.Info("Resource already exists and is managed by this vidraResource -> updating", "name", existing.GetName(), "namespace", existing.GetNamespace())
+		logger.Info("Resource already exists and is managed by this vidraResource -> applying", "name", existing.GetName(), "namespace", existing.GetNamespace())
+		// Keep the other owners, every vidraResource applies the full owner list
+		mergeOwners(desired, existing)
 		if r.isEqual(existing, desired) {
-			return nil
+			return nil, nil
 		}
-		desired.SetResourceVersion(existing.GetResourceVersion())
-		return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-			return destClient.Update(ctx, desired)
-		})
+		if !manifestChanged {
+			switch res.Spec.Destination.DriftPolicy {
+			case infrahubv1alpha1.DriftPolicyIgnore:
+				logger.Info("Ignoring drift of resource", "name", existing.GetName(), "namespace", existing.GetNamespace())
+				return nil, nil
+			case infrahubv1alpha1.DriftPolicyDetect:
+				logger.Info("Drift detected, not correcting it", "name", existing.GetName(), "namespace", existing.GetNamespace())
+				return r.diff(existing, desired), nil
+			}
+		}
+		return nil, r.serverSideApply(ctx, res, desired, destClient)
 	}
 
 	// Normalize spec maps before comparing
@@ -374,38 +547,52 @@ func (r *VidraResourceReconciler) applyResource(ctx context.Context, res *infrah
 			res.Status.LastError = fmt.Sprintf("Warning: resource is already managed by vidraResource: %s", existing.GetAnnotations()[OwnerAnnotation])
 		}); err != nil {
 			logger.Error(err, "Failed to update LastError with warning")
-			return err
+			return nil, err
 		}
-		return r.patchOwnerAnnotation(ctx, desired, existing, destClient)
+		return nil, r.patchOwnerAnnotation(ctx, desired, existing, destClient)
 	}
-	logger.Info("updating changed resource", "name", existing.GetName(), "namespace", existing.GetNamespace())
-	desired.SetResourceVersion(existing.GetResourceVersion())
-	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-		return destClient.Update(ctx, desired)
-	})
+	logger.Info("applying changed resource", "name", existing.GetName(), "namespace", existing.GetNamespace())
+	mergeOwners(desired, existing)
+	return nil, r.serverSideApply(ctx, res, desired, destClient)
 }
 
-func (r *VidraResourceReconciler) shouldUpdateResource(existing, desired *unstructured.Unstructured) bool {
-	existingAnnotations := existing.GetAnnotations()
-	desiredAnnotations := desired.GetAnnotations()
+// serverSideApply applies the desired resource with server-side apply using the field manager of the vidraResource.
+// Conflicts with other field managers are only forced if the destination of the vidraResource allows it.
+func (r *VidraResourceReconciler) serverSideApply(ctx context.Context, res *infrahubv1alpha1.VidraResource, desired *unstructured.Unstructured, destClient client.Client) error {
+	// Server-side apply does not accept managedFields and must not be bound to a resourceVersion
+	desired.SetManagedFields(nil)
+	desired.SetResourceVersion("")
+
+	opts := []client.PatchOption{client.FieldOwner(fieldManagerFor(res))}
+	if res.Spec.Destination.ForceConflicts {
+		opts = append(opts, client.ForceOwnership)
+	}
+	return destClient.Patch(ctx, desired, client.Apply, opts...)
+}
 
-	return existingAnnotations[OwnerAnnotation] == desiredAnnotations[OwnerAnnotation]
+func (r *VidraResourceReconciler) shouldUpdateResource(existing, desired *unstructured.Unstructured) bool {
+	existingOwners := strings.Split(existing.GetAnnotations()[OwnerAnnotation], ",")
+	return containsString(existingOwners, desired.GetAnnotations()[OwnerAnnotation])
 }
 
+// isEqual reports whether all fields of the desired resource are already set on the existing resource.
+// Fields that are only present on the existing resource (defaults, fields of other controllers) are ignored.
 func (r *VidraResourceReconciler) isEqual(existing, desired *unstructured.Unstructured) bool {
-	// Prepare deep copies for safe comparison
-	existingCopy := existing.DeepCopy()
-	desiredCopy := desired.DeepCopy()
-
-	// Remove finalizers, status, and metadata from both objects
-	r.removeFinalizers(existingCopy)
-	r.removeFinalizers(desiredCopy)
-	delete(existingCopy.Object, "status")
-	delete(desiredCopy.Object, "status")
-	delete(existingCopy.Object, "metadata")
-	delete(desiredCopy.Object, "metadata")
-
-	return equality.Semantic.DeepEqual(desiredCopy.Object, existingCopy.Object)
+	return isSubset(r.comparable(desired).Object, r.comparable(existing).Object)
+}
+
+// diff summarizes the fields of the desired resource which differ on the existing resource.
+func (r *VidraResourceReconciler) diff(existing, desired *unstructured.Unstructured) []string {
+	return fieldDiff("", r.comparable(desired).Object, r.comparable(existing).Object)
+}
+
+// comparable returns a deep copy of the resource without finalizers, status and metadata.
+func (r *VidraResourceReconciler) comparable(resource *unstructured.Unstructured) *unstructured.Unstructured {
+	resourceCopy := resource.DeepCopy()
+	r.removeFinalizers(resourceCopy)
+	delete(resourceCopy.Object, "status")
+	delete(resourceCopy.Object, "metadata")
+	return resourceCopy
 }
 
 func (r *VidraResourceReconciler) removeFinalizers(resource *unstructured.Unstructured) {
@@ -432,7 +619,7 @@ func (r *VidraResourceReconciler) patchOwnerAnnotation(ctx context.Context, desi
 
 	// Retry on conflict when patching annotations
 	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-		return destClient.Patch(ctx, existing, client.MergeFrom(patch))
+		return destClient.Patch(ctx, existing, client.MergeFrom(patch), client.FieldOwner(FieldManager))
 	})
 }
 
@@ -463,7 +650,9 @@ func (r *VidraResourceReconciler) removeFinalizer(ctx context.Context, obj *infr
 // Setup
 func (r *VidraResourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.InfrahubClient = infrahub.NewClient()
-	r.DynamicMulticlusterFactory = k8s.NewDynamicMulticlusterFactory()
+	if r.DynamicMulticlusterFactory == nil {
+		r.DynamicMulticlusterFactory = k8s.NewDynamicMulticlusterFactory()
+	}
 
 	// Create a direct (non-cached) client
 	cfg := mgr.GetConfig()
@@ -491,6 +680,11 @@ func (r *VidraResourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&infrahubv1alpha1.VidraResource{},
 			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.resourcesUsingKubeconfig),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				_, ok := obj.GetLabels()[k8s.ClusterKubeconfigLabel]
+				return ok
+			}))).
 		Complete(r)
 }
 
@@ -500,6 +694,7 @@ func (r *VidraResourceReconciler) InitConfigWithClient(ctx context.Context, k8sC
 
 	// Start with the default values
 	r.RequeueAfter = defaultRequeue
+	r.HealthChecker = k8s.NewHealthChecker()
 	var configMaps corev1.ConfigMapList
 	if err := k8s.GetSortedListByLabel(ctx, k8sClient, labelKey, labelValue, &configMaps); err != nil {
 		if strings.Contains(err.Error(), "no resources found with label") {
@@ -515,11 +710,14 @@ func (r *VidraResourceReconciler) InitConfigWithClient(ctx context.Context, k8sC
 
 	var configMap *corev1.ConfigMap
 	for _, cm := range configMaps.Items {
-		if cm.Data["requeueResourcesAfter"] != "" {
+		if cm.Data["requeueResourcesAfter"] != "" || cm.Data["healthRules"] != "" {
 			configMap = &cm
 			break
 		}
 	}
+	if configMap == nil {
+		return nil
+	}
 	// Check for 'requeueAfter' and update if available
 	requeueAfter, ok := configMap.Data["requeueResourcesAfter"]
 	if ok {
@@ -544,6 +742,15 @@ func (r *VidraResourceReconciler) InitConfigWithClient(ctx context.Context, k8sC
 		r.EventBasedReconcile = false
 	}
 
+	// Check for custom 'healthRules' (CEL expressions per GroupKind)
+	if healthRules, ok := configMap.Data["healthRules"]; ok {
+		healthChecker, err := k8s.NewHealthCheckerWithRules(healthRules)
+		if err != nil {
+			return err
+		}
+		r.HealthChecker = healthChecker
+	}
+
 	return nil
 }
 
@@ -574,19 +781,101 @@ func annotateWithOwner(obj *unstructured.Unstructured, owner string) {
 	ann["managed-by"] = vidraOperator
 	obj.SetAnnotations(ann)
 }
+
+// mergeOwners sets the owner annotation of desired to the owners of existing followed by the owner of desired,
+// so applying desired keeps the other vidraResources as owners
+func mergeOwners(desired, existing *unstructured.Unstructured) {
+	annotations := desired.GetAnnotations()
+	var owners []string
+	for _, owner := range strings.Split(existing.GetAnnotations()[OwnerAnnotation], ",") {
+		if owner != "" && !containsString(owners, owner) {
+			owners = append(owners, owner)
+		}
+	}
+	if owner := annotations[OwnerAnnotation]; owner != "" && !containsString(owners, owner) {
+		owners = append(owners, owner)
+	}
+	annotations[OwnerAnnotation] = strings.Join(owners, ",")
+	desired.SetAnnotations(annotations)
+}
+
+// fieldManagerFor returns the server-side apply field manager of the vidraResource. Each vidraResource has its own
+// field manager, so an apply only removes fields of its own manifest and keeps those of other vidraResources.
+func fieldManagerFor(res *infrahubv1alpha1.VidraResource) string {
+	return FieldManager + "/" + res.Name
+}
+
+// isVidraFieldManager reports whether the field manager is the one of a vidraResource
+func isVidraFieldManager(manager string) bool {
+	return manager == FieldManager || strings.HasPrefix(manager, FieldManager+"/")
+}
+
+// isManagedByVidra reports whether the resource is managed by vidra, either through the
+// managed-by annotation or through a server-side apply entry of a vidra field manager.
+func isManagedByVidra(obj *unstructured.Unstructured) bool {
+	if obj.GetAnnotations()["managed-by"] == vidraOperator {
+		return true
+	}
+	for _, entry := range obj.GetManagedFields() {
+		if isVidraFieldManager(entry.Manager) && entry.Operation == metav1.ManagedFieldsOperationApply {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubset reports whether every field of desired is present with the same value in existing.
+func isSubset(desired, existing interface{}) bool {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		e, ok := existing.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range d {
+			existingValue, found := e[key]
+			if !found {
+				if value == nil {
+					continue
+				}
+				return false
+			}
+			if !isSubset(value, existingValue) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		e, ok := existing.([]interface{})
+		if !ok || len(d) != len(e) {
+			return false
+		}
+		for i := range d {
+			if !isSubset(d[i], e[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return equality.Semantic.DeepEqual(desired, existing)
+	}
+}
+
 func resourceKey(res infrahubv1alpha1.ManagedResourceStatus) string {
 	return fmt.Sprintf("%s:%s:%s:%s", res.APIVersion, res.Kind, res.Namespace, res.Name)
 }
 
-func buildFinalResourceList(existing []infrahubv1alpha1.ManagedResourceStatus, new map[string]infrahubv1alpha1.ManagedResourceStatus) []infrahubv1alpha1.ManagedResourceStatus {
-	result := existing[:0]
+// mergeResourceLists updates the existing managed resources with the newly applied ones and keeps all others.
+func mergeResourceLists(existing []infrahubv1alpha1.ManagedResourceStatus, new map[string]infrahubv1alpha1.ManagedResourceStatus) []infrahubv1alpha1.ManagedResourceStatus {
+	result := make([]infrahubv1alpha1.ManagedResourceStatus, 0, len(existing)+len(new))
 	seen := map[string]bool{}
 	for _, r := range existing {
 		key := resourceKey(r)
-		if _, ok := new[key]; ok {
-			result = append(result, r)
-			seen[key] = true
+		if updated, ok := new[key]; ok {
+			r = updated
 		}
+		result = append(result, r)
+		seen[key] = true
 	}
 	for key, r := range new {
 		if !seen[key] {
@@ -5,6 +5,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"strings"
 	"syscall"
 	"time"
 
@@ -137,13 +138,21 @@ var _ = Describe("should reconcile correctly with different Destination.Server v
 						By("reconciling the resource on the destination server")
 						deployK8sClient := setupDynamicMulticlusterFactoryMock(ctx, k8sClient, mockDynamicMulticlusterFactory, namespacedName, secondK8sClient)
 
-						_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+						result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
 						Expect(err).NotTo(HaveOccurred())
+						Expect(result.RequeueAfter).To(BeZero())
 
 						Eventually(func() error {
 							cm := &v1.ConfigMap{}
 							return deployK8sClient.Get(ctx, types.NamespacedName{Name: "example", Namespace: namespace}, cm)
 						}).Should(Succeed())
+
+						By("checking the health of the managed ConfigMap")
+						Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+						Expect(instance.Status.Health).To(Equal(infrahubv1alpha1.HealthHealthy))
+						Expect(instance.Status.ManagedResources).To(HaveLen(1))
+						Expect(instance.Status.ManagedResources[0].Health).To(Equal(infrahubv1alpha1.HealthHealthy))
+						Expect(meta.IsStatusConditionTrue(instance.Status.Conditions, infrahubv1alpha1.ConditionHealthy)).To(BeTrue())
 					})
 
 					It("should reconcile multiple resources from artifact", func() {
@@ -398,9 +407,15 @@ spec:
 							AnyTimes()
 						deployK8sClient := setupDynamicMulticlusterFactoryMock(ctx, k8sClient, mockDynamicMulticlusterFactory, namespacedName, secondK8sClient)
 						// Run reconciliation
-						_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+						result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
 						Expect(err).NotTo(HaveOccurred())
 
+						// No controller rolls out the Deployment in the test environment
+						Expect(result.RequeueAfter).To(Equal(healthRequeueInterval))
+						Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+						Expect(instance.Status.Health).To(Equal(infrahubv1alpha1.HealthProgressing))
+						Expect(meta.IsStatusConditionFalse(instance.Status.Conditions, infrahubv1alpha1.ConditionHealthy)).To(BeTrue())
+
 						// Check Deployment exists and has 3 replicas
 						deploy := &appsv1.Deployment{}
 						Eventually(func(g Gomega) {
@@ -420,6 +435,82 @@ spec:
 						Expect(ing.Spec.Rules).NotTo(BeEmpty())
 					})
 
+					It("should wait for a sync wave to become healthy before applying the next wave", func() {
+						manifest := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: wave-one
+  namespace: default
+  annotations:
+    vidra.infrahub.operators.com/sync-wave: "1"
+data:
+  key: value
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: wave-zero
+  namespace: default
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: wave-zero
+  template:
+    metadata:
+      labels:
+        app: wave-zero
+    spec:
+      containers:
+      - name: www
+        image: nginx
+`
+						instance := &infrahubv1alpha1.VidraResource{}
+						Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+						instance.Spec.Manifest = manifest
+						Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+
+						mockRESTMapper.EXPECT().
+							RESTMapping(gomock.Any(), gomock.Any()).
+							Return(&meta.RESTMapping{Scope: meta.RESTScopeNamespace}, nil).
+							AnyTimes()
+						deployK8sClient := setupDynamicMulticlusterFactoryMock(ctx, k8sClient, mockDynamicMulticlusterFactory, namespacedName, secondK8sClient)
+
+						result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result.RequeueAfter).To(Equal(healthRequeueInterval))
+
+						By("checking that only the first wave is applied while its Deployment is not rolled out")
+						Expect(deployK8sClient.Get(ctx, client.ObjectKey{Name: "wave-zero", Namespace: "default"}, &appsv1.Deployment{})).To(Succeed())
+						err = deployK8sClient.Get(ctx, client.ObjectKey{Name: "wave-one", Namespace: "default"}, &v1.ConfigMap{})
+						Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+
+						Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+						applied := meta.FindStatusCondition(instance.Status.Conditions, infrahubv1alpha1.ConditionApplied)
+						Expect(applied).NotTo(BeNil())
+						Expect(applied.Reason).To(Equal(infrahubv1alpha1.ReasonWaitingForSyncWave))
+						Expect(instance.Status.ManagedResources).To(HaveLen(1))
+
+						By("applying the next wave once the Deployment is rolled out")
+						deploy := &appsv1.Deployment{}
+						Expect(deployK8sClient.Get(ctx, client.ObjectKey{Name: "wave-zero", Namespace: "default"}, deploy)).To(Succeed())
+						deploy.Status.ObservedGeneration = deploy.Generation
+						deploy.Status.Replicas = 1
+						deploy.Status.UpdatedReplicas = 1
+						deploy.Status.AvailableReplicas = 1
+						deploy.Status.ReadyReplicas = 1
+						Expect(deployK8sClient.Status().Update(ctx, deploy)).To(Succeed())
+
+						_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+						Expect(err).NotTo(HaveOccurred())
+						Expect(deployK8sClient.Get(ctx, client.ObjectKey{Name: "wave-one", Namespace: "default"}, &v1.ConfigMap{})).To(Succeed())
+
+						Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+						Expect(meta.IsStatusConditionTrue(instance.Status.Conditions, infrahubv1alpha1.ConditionApplied)).To(BeTrue())
+						Expect(instance.Status.ManagedResources).To(HaveLen(2))
+					})
+
 					It("should reconcile the cached resource form the crd if the manifest does not change", func() {
 						By("setting up the mock client to return a YAML with a resource")
 						yamlData := `
@@ -468,7 +559,7 @@ data:
 						}).Should(Succeed())
 					})
 
-					It("should overwrite the resource if it was manually changed", func() {
+					It("should overwrite the resource if it was manually changed and conflicts are forced", func() {
 						By("setting up the mock client to return a YAML with a resource")
 						yamlData := `
 apiVersion: v1
@@ -483,6 +574,7 @@ data:
 						err := k8sClient.Get(ctx, namespacedName, instance)
 						Expect(err).NotTo(HaveOccurred())
 						instance.Spec.Manifest = yamlData
+						instance.Spec.Destination.ForceConflicts = true
 						Expect(k8sClient.Update(ctx, instance)).To(Succeed())
 
 						mockRESTMapper.EXPECT().
@@ -548,6 +640,239 @@ data:
 						}).Should(Succeed())
 					})
 
+					It("should not overwrite a manually changed field without forcing conflicts", func() {
+						By("setting up the mock client to return a YAML with a resource")
+						yamlData := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: conflict-resource
+  namespace: ` + namespace + `
+data:
+  key: value
+`
+						instance := &infrahubv1alpha1.VidraResource{}
+						err := k8sClient.Get(ctx, namespacedName, instance)
+						Expect(err).NotTo(HaveOccurred())
+						instance.Spec.Manifest = yamlData
+						Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+
+						mockRESTMapper.EXPECT().
+							RESTMapping(schema.GroupKind{Group: "", Kind: "ConfigMap"}, "v1").
+							Return(&meta.RESTMapping{
+								Resource: schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"},
+								Scope:    meta.RESTScopeNamespace,
+							}, nil).AnyTimes()
+						By("reconciling the resource on the destination server")
+						deployK8sClient := setupDynamicMulticlusterFactoryMock(ctx, k8sClient, mockDynamicMulticlusterFactory, namespacedName, secondK8sClient)
+						_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+						Expect(err).NotTo(HaveOccurred())
+
+						By("changing the field with another field manager")
+						cm := &v1.ConfigMap{}
+						Expect(deployK8sClient.Get(ctx, types.NamespacedName{Name: "conflict-resource", Namespace: namespace}, cm)).To(Succeed())
+						Expect(cm.ManagedFields).To(ContainElement(WithTransform(func(entry metav1.ManagedFieldsEntry) string {
+							return entry.Manager
+						}, Equal(FieldManager+"/"+namespacedName.Name))))
+						cm.Data["key"] = "new-value"
+						Expect(deployK8sClient.Update(ctx, cm, client.FieldOwner("kubectl-edit"))).To(Succeed())
+
+						By("reconciling again without forceConflicts")
+						_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+						Expect(err).To(HaveOccurred())
+						Expect(k8serrors.IsConflict(err)).To(BeTrue())
+
+						Expect(deployK8sClient.Get(ctx, types.NamespacedName{Name: "conflict-resource", Namespace: namespace}, cm)).To(Succeed())
+						Expect(cm.Data["key"]).To(Equal("new-value"))
+						Expect(deployK8sClient.Delete(ctx, cm)).To(Succeed())
+					})
+
+					It("should report but not correct drift if the drift policy is detect", func() {
+						yamlData := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: drift-resource
+  namespace: ` + namespace + `
+data:
+  key: value
+`
+						instance := &infrahubv1alpha1.VidraResource{}
+						Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+						instance.Spec.Manifest = yamlData
+						instance.Spec.Destination.DriftPolicy = infrahubv1alpha1.DriftPolicyDetect
+						Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+
+						mockRESTMapper.EXPECT().
+							RESTMapping(gomock.Any(), gomock.Any()).
+							Return(&meta.RESTMapping{Scope: meta.RESTScopeNamespace}, nil).
+							AnyTimes()
+						deployK8sClient := setupDynamicMulticlusterFactoryMock(ctx, k8sClient, mockDynamicMulticlusterFactory, namespacedName, secondK8sClient)
+						_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+						Expect(err).NotTo(HaveOccurred())
+
+						By("changing the field manually")
+						cm := &v1.ConfigMap{}
+						Expect(deployK8sClient.Get(ctx, types.NamespacedName{Name: "drift-resource", Namespace: namespace}, cm)).To(Succeed())
+						cm.Data["key"] = "changed"
+						Expect(deployK8sClient.Update(ctx, cm)).To(Succeed())
+
+						_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(deployK8sClient.Get(ctx, types.NamespacedName{Name: "drift-resource", Namespace: namespace}, cm)).To(Succeed())
+						Expect(cm.Data["key"]).To(Equal("changed"))
+						Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+						Expect(instance.Status.Drifted).To(HaveLen(1))
+						Expect(instance.Status.Drifted[0].Name).To(Equal("drift-resource"))
+						Expect(instance.Status.Drifted[0].Diff).To(Equal([]string{"data.key: want value, got changed"}))
+
+						By("applying a changed manifest even though the resource drifted")
+						instance.Spec.Manifest = strings.Replace(yamlData, "key: value", "key: new-value", 1)
+						// The manual change took over the field, so the new value has to be forced
+						instance.Spec.Destination.ForceConflicts = true
+						Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+						_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(deployK8sClient.Get(ctx, types.NamespacedName{Name: "drift-resource", Namespace: namespace}, cm)).To(Succeed())
+						Expect(cm.Data["key"]).To(Equal("new-value"))
+						Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+						Expect(instance.Status.Drifted).To(BeEmpty())
+					})
+
+					It("should neither report nor correct drift if the drift policy is ignore", func() {
+						yamlData := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ignored-drift-resource
+  namespace: ` + namespace + `
+data:
+  key: value
+`
+						instance := &infrahubv1alpha1.VidraResource{}
+						Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+						instance.Spec.Manifest = yamlData
+						instance.Spec.Destination.DriftPolicy = infrahubv1alpha1.DriftPolicyIgnore
+						Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+
+						mockRESTMapper.EXPECT().
+							RESTMapping(gomock.Any(), gomock.Any()).
+							Return(&meta.RESTMapping{Scope: meta.RESTScopeNamespace}, nil).
+							AnyTimes()
+						deployK8sClient := setupDynamicMulticlusterFactoryMock(ctx, k8sClient, mockDynamicMulticlusterFactory, namespacedName, secondK8sClient)
+						_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+						Expect(err).NotTo(HaveOccurred())
+
+						cm := &v1.ConfigMap{}
+						Expect(deployK8sClient.Get(ctx, types.NamespacedName{Name: "ignored-drift-resource", Namespace: namespace}, cm)).To(Succeed())
+						cm.Data["key"] = "changed"
+						Expect(deployK8sClient.Update(ctx, cm)).To(Succeed())
+
+						_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(deployK8sClient.Get(ctx, types.NamespacedName{Name: "ignored-drift-resource", Namespace: namespace}, cm)).To(Succeed())
+						Expect(cm.Data["key"]).To(Equal("changed"))
+						Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+						Expect(instance.Status.Drifted).To(BeEmpty())
+					})
+
+					It("should neither compare nor apply ignored differences", func() {
+						yamlData := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ignored-field-resource
+  namespace: ` + namespace + `
+data:
+  key: value
+  scaled: "1"
+`
+						instance := &infrahubv1alpha1.VidraResource{}
+						Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+						instance.Spec.Manifest = yamlData
+						instance.Spec.Destination.IgnoreDifferences = []infrahubv1alpha1.ResourceIgnoreDifferences{{
+							Kind:         "ConfigMap",
+							Name:         "ignored-field-resource",
+							JSONPointers: []string{"/data/scaled"},
+						}}
+						Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+
+						mockRESTMapper.EXPECT().
+							RESTMapping(gomock.Any(), gomock.Any()).
+							Return(&meta.RESTMapping{Scope: meta.RESTScopeNamespace}, nil).
+							AnyTimes()
+						deployK8sClient := setupDynamicMulticlusterFactoryMock(ctx, k8sClient, mockDynamicMulticlusterFactory, namespacedName, secondK8sClient)
+						_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+						Expect(err).NotTo(HaveOccurred())
+
+						cm := &v1.ConfigMap{}
+						Expect(deployK8sClient.Get(ctx, types.NamespacedName{Name: "ignored-field-resource", Namespace: namespace}, cm)).To(Succeed())
+						Expect(cm.Data).NotTo(HaveKey("scaled"))
+
+						By("setting the ignored field with another controller")
+						cm.Data["scaled"] = "5"
+						Expect(deployK8sClient.Update(ctx, cm, client.FieldOwner("autoscaler"))).To(Succeed())
+
+						_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(deployK8sClient.Get(ctx, types.NamespacedName{Name: "ignored-field-resource", Namespace: namespace}, cm)).To(Succeed())
+						Expect(cm.Data["scaled"]).To(Equal("5"))
+						Expect(cm.Data["key"]).To(Equal("value"))
+					})
+
+					It("should keep a previously applied field once it is ignored", func() {
+						yamlData := `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: later-ignored-resource
+  namespace: ` + namespace + `
+data:
+  key: value
+  scaled: "1"
+`
+						instance := &infrahubv1alpha1.VidraResource{}
+						Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+						instance.Spec.Manifest = yamlData
+						Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+
+						mockRESTMapper.EXPECT().
+							RESTMapping(gomock.Any(), gomock.Any()).
+							Return(&meta.RESTMapping{Scope: meta.RESTScopeNamespace}, nil).
+							AnyTimes()
+						deployK8sClient := setupDynamicMulticlusterFactoryMock(ctx, k8sClient, mockDynamicMulticlusterFactory, namespacedName, secondK8sClient)
+						_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+						Expect(err).NotTo(HaveOccurred())
+
+						By("ignoring the applied field")
+						Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+						instance.Spec.Destination.IgnoreDifferences = []infrahubv1alpha1.ResourceIgnoreDifferences{{
+							Kind:         "ConfigMap",
+							Name:         "later-ignored-resource",
+							JSONPointers: []string{"/data/scaled"},
+						}}
+						Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+						_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+						Expect(err).NotTo(HaveOccurred())
+
+						cm := &v1.ConfigMap{}
+						Expect(deployK8sClient.Get(ctx, types.NamespacedName{Name: "later-ignored-resource", Namespace: namespace}, cm)).To(Succeed())
+						Expect(cm.Data).To(HaveKeyWithValue("scaled", "1"))
+
+						By("changing the ignored field with another controller")
+						cm.Data["scaled"] = "5"
+						Expect(deployK8sClient.Update(ctx, cm, client.FieldOwner("autoscaler"))).To(Succeed())
+						_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(deployK8sClient.Get(ctx, types.NamespacedName{Name: "later-ignored-resource", Namespace: namespace}, cm)).To(Succeed())
+						Expect(cm.Data).To(HaveKeyWithValue("scaled", "5"))
+					})
+
 					It("should reconcile resources in to its namespace if a namespace is in the artifact", func() {
 						By("setting up the mock client to return a YAML with a namespace and resources in it")
 
@@ -909,6 +1234,165 @@ metadata:
 
 					})
 
+					Context("with a prune policy", func() {
+						twoConfigMaps := func(prefix string, pruneAnnotation bool) string {
+							annotations := ""
+							if pruneAnnotation {
+								annotations = `
+  annotations:
+    ` + PruneAnnotation + `: "false"`
+							}
+							return `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ` + prefix + `-a
+  namespace: ` + namespace + `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ` + prefix + `-b
+  namespace: ` + namespace + annotations + `
+`
+						}
+						getConfigMap := func(deployK8sClient client.Client, name string) (*unstructured.Unstructured, error) {
+							cm := &unstructured.Unstructured{}
+							cm.SetAPIVersion("v1")
+							cm.SetKind("ConfigMap")
+							err := deployK8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, cm)
+							return cm, err
+						}
+						// applyAndRemove applies both ConfigMaps and then a manifest without the second one
+						applyAndRemove := func(prefix string, destination func(*infrahubv1alpha1.InfrahubSyncDestination), pruneAnnotation bool) client.Client {
+							instance := &infrahubv1alpha1.VidraResource{}
+							Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+							destination(&instance.Spec.Destination)
+							instance.Spec.Manifest = twoConfigMaps(prefix, pruneAnnotation)
+							Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+
+							mockRESTMapper.EXPECT().
+								RESTMapping(schema.GroupKind{Group: "", Kind: "ConfigMap"}, "v1").
+								Return(&meta.RESTMapping{
+									Resource: schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"},
+									Scope:    meta.RESTScopeNamespace,
+								}, nil).AnyTimes()
+							deployK8sClient := setupDynamicMulticlusterFactoryMock(ctx, k8sClient, mockDynamicMulticlusterFactory, namespacedName, secondK8sClient)
+
+							_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+							Expect(err).NotTo(HaveOccurred())
+
+							Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+							instance.Spec.Manifest = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ` + prefix + `-a
+  namespace: ` + namespace + `
+`
+							Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+							_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+							Expect(err).NotTo(HaveOccurred())
+							return deployK8sClient
+						}
+
+						It("should refuse to prune more than maxPrunePercent of the managed resources", func() {
+							deployK8sClient := applyAndRemove("threshold", func(d *infrahubv1alpha1.InfrahubSyncDestination) {
+								d.MaxPrunePercent = 40
+							}, false)
+
+							_, err := getConfigMap(deployK8sClient, "threshold-b")
+							Expect(err).NotTo(HaveOccurred())
+
+							instance := &infrahubv1alpha1.VidraResource{}
+							Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+							Expect(instance.Status.DeployState).To(Equal(infrahubv1alpha1.StateStale))
+							Expect(instance.Status.ManagedResources).To(HaveLen(2))
+							pruned := meta.FindStatusCondition(instance.Status.Conditions, infrahubv1alpha1.ConditionPruned)
+							Expect(pruned).NotTo(BeNil())
+							Expect(pruned.Status).To(Equal(metav1.ConditionFalse))
+							Expect(pruned.Reason).To(Equal(infrahubv1alpha1.ReasonPruneThresholdExceeded))
+							Expect(pruned.Message).To(ContainSubstring("refusing to prune 1 of 2 resources"))
+							Expect(meta.IsStatusConditionFalse(instance.Status.Conditions, infrahubv1alpha1.ConditionReady)).To(BeTrue())
+						})
+
+						It("should keep stale resources if pruning is disabled", func() {
+							deployK8sClient := applyAndRemove("disabled", func(d *infrahubv1alpha1.InfrahubSyncDestination) {
+								d.PrunePolicy = infrahubv1alpha1.PrunePolicyDisabled
+							}, false)
+
+							_, err := getConfigMap(deployK8sClient, "disabled-b")
+							Expect(err).NotTo(HaveOccurred())
+
+							instance := &infrahubv1alpha1.VidraResource{}
+							Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+							Expect(instance.Status.DeployState).To(Equal(infrahubv1alpha1.StateSucceeded))
+							Expect(instance.Status.ManagedResources).To(HaveLen(2))
+							pruned := meta.FindStatusCondition(instance.Status.Conditions, infrahubv1alpha1.ConditionPruned)
+							Expect(pruned).NotTo(BeNil())
+							Expect(pruned.Reason).To(Equal(infrahubv1alpha1.ReasonPruneDisabled))
+						})
+
+						It("should orphan stale resources instead of deleting them", func() {
+							deployK8sClient := applyAndRemove("orphan", func(d *infrahubv1alpha1.InfrahubSyncDestination) {
+								d.PrunePolicy = infrahubv1alpha1.PrunePolicyOrphan
+							}, false)
+
+							cm, err := getConfigMap(deployK8sClient, "orphan-b")
+							Expect(err).NotTo(HaveOccurred())
+							Expect(cm.GetAnnotations()).NotTo(HaveKey(OwnerAnnotation))
+							Expect(cm.GetOwnerReferences()).To(BeEmpty())
+
+							instance := &infrahubv1alpha1.VidraResource{}
+							Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+							Expect(instance.Status.ManagedResources).To(HaveLen(1))
+							Expect(meta.IsStatusConditionTrue(instance.Status.Conditions, infrahubv1alpha1.ConditionPruned)).To(BeTrue())
+						})
+
+						It("should orphan stale resources with the prune annotation set to false", func() {
+							deployK8sClient := applyAndRemove("annotated", func(d *infrahubv1alpha1.InfrahubSyncDestination) {}, true)
+
+							cm, err := getConfigMap(deployK8sClient, "annotated-b")
+							Expect(err).NotTo(HaveOccurred())
+							Expect(cm.GetAnnotations()).NotTo(HaveKey(OwnerAnnotation))
+
+							_, err = getConfigMap(deployK8sClient, "annotated-a")
+							Expect(err).NotTo(HaveOccurred())
+						})
+					})
+
+					It("should neither apply nor prune while suspended", func() {
+						instance := &infrahubv1alpha1.VidraResource{}
+						Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+						instance.Spec.Suspend = true
+						instance.Spec.Manifest = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: suspended-config
+  namespace: ` + namespace + `
+`
+						Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+
+						By("reconciling the suspended resource without a destination client")
+						result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
+						Expect(err).NotTo(HaveOccurred())
+						Expect(result).To(Equal(reconcile.Result{}))
+
+						Expect(k8sClient.Get(ctx, namespacedName, instance)).To(Succeed())
+						Expect(meta.IsStatusConditionTrue(instance.Status.Conditions, infrahubv1alpha1.ConditionSuspended)).To(BeTrue())
+						Expect(instance.Status.ManagedResources).To(BeEmpty())
+
+						cm := &unstructured.Unstructured{}
+						cm.SetAPIVersion("v1")
+						cm.SetKind("ConfigMap")
+						err = secondK8sClient.Get(ctx, types.NamespacedName{Name: "suspended-config", Namespace: namespace}, cm)
+						Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+
+						instance.Spec.Suspend = false
+						Expect(k8sClient.Update(ctx, instance)).To(Succeed())
+					})
+
 					It("should skip if the managed resource is not found anymore during deletion", func() {
 						By("setting up the mock client to return a YAML with resource-a")
 						yamlData := `
@@ -1296,22 +1780,39 @@ metadata:
 
 						deployK8sClient := setupDynamicMulticlusterFactoryMock(ctx, k8sClient, mockDynamicMulticlusterFactory, namespacedName, secondK8sClient)
 						By("mocking the WatcherFactory to expect watching setup")
-						mockWatcherFactory.EXPECT().
-							StartWatchingGVRs(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(func(*unstructured.Unstructured, schema.GroupVersionResource) {})).
-							Do(func(_ dynamic.Interface, _ []schema.GroupVersionResource, cb domain.ResourceCallback) {
-								By("simulating an external event")
-								u := &unstructured.Unstructured{}
-								u.SetAPIVersion("vidra.simli.dev/v1alpha1")
-								u.SetKind("ConfigMap")
-								u.SetNamespace(namespace)
-								u.SetName("example")
-								u.SetOwnerReferences([]metav1.OwnerReference{{
-									APIVersion: infrahubv1alpha1.GroupVersion.String(),
-									Kind:       "VidraResource",
-									Name:       instance.Name,
-								}})
-								cb(u, schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"})
-							})
+						if destinationServer == "" {
+							mockWatcherFactory.EXPECT().
+								StartWatchingGVRs(gomock.Any(), gomock.Any(), gomock.AssignableToTypeOf(func(*unstructured.Unstructured, schema.GroupVersionResource) {})).
+								Do(func(_ dynamic.Interface, _ []schema.GroupVersionResource, cb domain.ResourceCallback) {
+									By("simulating an external event")
+									u := &unstructured.Unstructured{}
+									u.SetAPIVersion("vidra.simli.dev/v1alpha1")
+									u.SetKind("ConfigMap")
+									u.SetNamespace(namespace)
+									u.SetName("example")
+									u.SetOwnerReferences([]metav1.OwnerReference{{
+										APIVersion: infrahubv1alpha1.GroupVersion.String(),
+										Kind:       "VidraResource",
+										Name:       instance.Name,
+									}})
+									cb(u, schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"})
+								})
+						} else {
+							mockDynamicMulticlusterFactory.EXPECT().GetDynamicClientFor(deployK8sClient).Return(dynClient, nil)
+							mockWatcherFactory.EXPECT().
+								StartWatchingClusterGVRs("server/"+destinationServer, dynClient,
+									[]schema.GroupVersionResource{{Version: "v1", Resource: "configmaps"}}, gomock.Any()).
+								Do(func(_ string, _ dynamic.Interface, _ []schema.GroupVersionResource, cb domain.ResourceCallback) {
+									By("simulating an event on the remote cluster, owned through the annotation")
+									u := &unstructured.Unstructured{}
+									u.SetAPIVersion("v1")
+									u.SetKind("ConfigMap")
+									u.SetNamespace(namespace)
+									u.SetName("example")
+									u.SetAnnotations(map[string]string{OwnerAnnotation: instance.Name})
+									cb(u, schema.GroupVersionResource{Group: "", Version: "v1", Resource: "configmaps"})
+								})
+						}
 
 						By("reconciling the resource")
 						_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: namespacedName})
@@ -1507,7 +2008,7 @@ metadata:
 
 						// Act
 						if failingClient, ok := failingK8sClient.(*mock.FailingUpdateClient); ok {
-							failingClient.FailingMethod = "Create"
+							failingClient.FailingMethod = "Apply"
 						}
 
 						setupDynamicMulticlusterFactoryMock(ctx, failingK8sClient, mockDynamicMulticlusterFactory, namespacedName, failingK8sClient)
@@ -1516,7 +2017,7 @@ metadata:
 
 						// Assert: Check for the expected error
 						Expect(err).To(HaveOccurred())
-						Expect(err.Error()).To(ContainSubstring("simulated failure: Create - &{map[apiVersion:v1 data:map[key1:value1]"))
+						Expect(err.Error()).To(ContainSubstring("simulated failure: Apply - &{map[apiVersion:v1 data:map[key1:value1]"))
 						// Assert: Ensure the state of the VidraResource is marked as failed
 						err = k8sClient.Get(ctx, namespacedName, instance)
 						Expect(err).NotTo(HaveOccurred())
@@ -1583,7 +2084,7 @@ metadata:
 							DynamicMulticlusterFactory: mockDynamicMulticlusterFactory,
 						}
 						if failingClient, ok := failingK8sClient.(*mock.FailingUpdateClient); ok {
-							failingClient.FailingMethod = "Update"
+							failingClient.FailingMethod = "Apply"
 						}
 
 						By("reconciling the resource on the destination server simulating a failure during update")
@@ -1591,7 +2092,7 @@ metadata:
 
 						// Assert: Check that the resource was updated successfully
 						Expect(err).To(HaveOccurred())
-						Expect(err.Error()).To(ContainSubstring("simulated failure: Update - &{map[apiVersion:v1 data:map[key1:updated-value]"))
+						Expect(err.Error()).To(ContainSubstring("simulated failure: Apply - &{map[apiVersion:v1 data:map[key1:updated-value]"))
 
 						// Fetch the updated ConfigMap
 						notUpdatedConfigMap := &v1.ConfigMap{}
@@ -1856,4 +2357,146 @@ var _ = Describe("VidraResourceReconciler SetupWithManager", func() {
 		Expect(reconciler.RequeueAfter).To(Equal(12 * time.Minute))
 		Expect(reconciler.EventBasedReconcile).To(BeTrue(), "EventBasedReconcile should be true")
 	})
+
+	It("should fail to initialize the config if a custom health rule does not compile", func() {
+		configMap := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "vidra-config-health",
+				Namespace: "default",
+				Labels: map[string]string{
+					"app": "vidra-health",
+				},
+			},
+			Data: map[string]string{
+				"healthRules": `Deployment.apps: "object.status.("`,
+			},
+		}
+		Expect(k8sClient.Create(ctx, configMap)).To(Succeed())
+		defer func() {
+			Expect(k8sClient.Delete(ctx, configMap)).To(Succeed())
+		}()
+
+		err := reconciler.InitConfigWithClient(ctx, k8sClient, "app", "vidra-health")
+		Expect(err).To(MatchError(ContainSubstring("failed to compile health rule")))
+	})
+})
+
+var _ = Describe("VidraResourceReconciler isEqual", func() {
+	reconciler := &VidraResourceReconciler{}
+
+	newConfigMap := func(data map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "example"},
+		}}
+		if data != nil {
+			u.Object["data"] = data
+		}
+		return u
+	}
+
+	It("should ignore fields that are only set on the existing resource", func() {
+		existing := newConfigMap(map[string]interface{}{"key": "value", "injected": "by-webhook"})
+		existing.Object["status"] = map[string]interface{}{"ready": true}
+		desired := newConfigMap(map[string]interface{}{"key": "value"})
+		Expect(reconciler.isEqual(existing, desired)).To(BeTrue())
+	})
+
+	It("should detect changed fields of the desired resource", func() {
+		existing := newConfigMap(map[string]interface{}{"key": "changed"})
+		desired := newConfigMap(map[string]interface{}{"key": "value"})
+		Expect(reconciler.isEqual(existing, desired)).To(BeFalse())
+	})
+
+	It("should detect list fields with a different length", func() {
+		existing := newConfigMap(nil)
+		existing.Object["spec"] = map[string]interface{}{"items": []interface{}{"a", "b"}}
+		desired := newConfigMap(nil)
+		desired.Object["spec"] = map[string]interface{}{"items": []interface{}{"a"}}
+		Expect(reconciler.isEqual(existing, desired)).To(BeFalse())
+	})
+})
+
+var _ = Describe("isManagedByVidra", func() {
+	It("should accept resources with the managed-by annotation", func() {
+		u := &unstructured.Unstructured{}
+		u.SetAnnotations(map[string]string{"managed-by": vidraOperator})
+		Expect(isManagedByVidra(u)).To(BeTrue())
+	})
+
+	It("should accept resources applied by the vidra field manager", func() {
+		u := &unstructured.Unstructured{}
+		u.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: FieldManager, Operation: metav1.ManagedFieldsOperationApply}})
+		Expect(isManagedByVidra(u)).To(BeTrue())
+	})
+
+	It("should accept resources applied by the field manager of a vidraResource", func() {
+		u := &unstructured.Unstructured{}
+		res := &infrahubv1alpha1.VidraResource{ObjectMeta: metav1.ObjectMeta{Name: "web"}}
+		u.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: fieldManagerFor(res), Operation: metav1.ManagedFieldsOperationApply}})
+		Expect(fieldManagerFor(res)).To(Equal("vidra/web"))
+		Expect(isManagedByVidra(u)).To(BeTrue())
+	})
+
+	It("should reject field managers which only share the prefix", func() {
+		u := &unstructured.Unstructured{}
+		u.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "vidra-cli", Operation: metav1.ManagedFieldsOperationApply}})
+		Expect(isManagedByVidra(u)).To(BeFalse())
+	})
+
+	It("should reject resources of other field managers", func() {
+		u := &unstructured.Unstructured{}
+		u.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationUpdate}})
+		Expect(isManagedByVidra(u)).To(BeFalse())
+	})
+})
+
+var _ = Describe("mergeOwners", func() {
+	newOwned := func(owners string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAnnotations(map[string]string{OwnerAnnotation: owners})
+		return u
+	}
+
+	It("should add the owner of the desired resource to the existing owners", func() {
+		desired := newOwned("web")
+		mergeOwners(desired, newOwned("db,api"))
+		Expect(desired.GetAnnotations()).To(HaveKeyWithValue(OwnerAnnotation, "db,api,web"))
+	})
+
+	It("should not duplicate an existing owner", func() {
+		desired := newOwned("web")
+		mergeOwners(desired, newOwned("web,db"))
+		Expect(desired.GetAnnotations()).To(HaveKeyWithValue(OwnerAnnotation, "web,db"))
+	})
+
+	It("should only keep the owner of the desired resource if there are no existing owners", func() {
+		desired := newOwned("web")
+		mergeOwners(desired, &unstructured.Unstructured{})
+		Expect(desired.GetAnnotations()).To(HaveKeyWithValue(OwnerAnnotation, "web"))
+	})
+})
+
+var _ = Describe("fieldDiff", func() {
+	It("should list changed, missing and resized fields of the desired object", func() {
+		desired := map[string]interface{}{
+			"data": map[string]interface{}{"a": "1", "b": "2"},
+			"spec": map[string]interface{}{"ports": []interface{}{int64(80)}},
+		}
+		existing := map[string]interface{}{
+			"data": map[string]interface{}{"a": "changed", "extra": "ignored"},
+			"spec": map[string]interface{}{"ports": []interface{}{int64(80), int64(443)}},
+		}
+		Expect(fieldDiff("", desired, existing)).To(Equal([]string{
+			"data.a: want 1, got changed",
+			"data.b: missing",
+			"spec.ports: want 1 items, got 2",
+		}))
+	})
+
+	It("should return nothing for equal objects", func() {
+		obj := map[string]interface{}{"data": map[string]interface{}{"a": "1"}}
+		Expect(fieldDiff("", obj, obj)).To(BeEmpty())
+	})
 })
@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"log"
+	"strings"
 	"time"
 
 	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
@@ -11,6 +12,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // Callback function to warch_resources_factory
@@ -23,26 +25,99 @@ func (r *VidraResourceReconciler) handleLabeledResource(obj *unstructured.Unstru
 func (r *VidraResourceReconciler) triggerReconcileForOwner(obj *unstructured.Unstructured) {
 	for _, owner := range obj.GetOwnerReferences() {
 		if owner.Kind == "VidraResource" && owner.APIVersion == infrahubv1alpha1.GroupVersion.String() {
-			var res infrahubv1alpha1.VidraResource
-			err := r.Get(context.Background(), types.NamespacedName{
-				Name:      owner.Name,
-				Namespace: obj.GetNamespace(), // You were missing this
-			}, &res)
-			if err != nil {
-				log.Printf("[WATCH] Failed to get VidraResource %s/%s: %v", obj.GetNamespace(), owner.Name, err)
-				continue
-			}
-
-			if res.Spec.ReconciledAt.Time.Before(time.Now().Add(-2 * time.Second)) {
-				res.Spec.ReconciledAt = v1.Time{Time: time.Now()}
-				if err := r.Update(context.Background(), &res); err != nil {
-					log.Printf("[WATCH] Failed to update VidraResource %s/%s: %v", res.Namespace, res.Name, err)
-				} else {
-					log.Printf("[WATCH] Triggered reconcile of VidraResource %s/%s", res.Namespace, res.Name)
-				}
-			} else {
-				log.Printf("[WATCH] VidraResource %s/%s is already up-to-date, skipping reconcile trigger", res.Namespace, res.Name)
-			}
+			r.triggerReconcile(owner.Name, obj.GetNamespace())
 		}
 	}
 }
+
+// handleRemoteResource triggers the VidraResources owning a changed resource of a remote cluster. Owner references
+// cannot point to another cluster, so the owners are read from the owner annotation.
+func (r *VidraResourceReconciler) handleRemoteResource(obj *unstructured.Unstructured, gvr schema.GroupVersionResource) {
+	log.Printf("[WATCH] Change detected on remote resource: %s/%s (%s)", obj.GetNamespace(), obj.GetName(), gvr.Resource)
+	for _, owner := range strings.Split(obj.GetAnnotations()[OwnerAnnotation], ",") {
+		if owner != "" {
+			// VidraResources are cluster-scoped, the namespace of the remote resource does not apply
+			r.triggerReconcile(owner, "")
+		}
+	}
+}
+
+// triggerReconcile bumps reconciledAt of the VidraResource, unless it is suspended, ignores drift or was just reconciled
+func (r *VidraResourceReconciler) triggerReconcile(name, namespace string) {
+	var res infrahubv1alpha1.VidraResource
+	err := r.Get(context.Background(), types.NamespacedName{
+		Name:      name,
+		Namespace: namespace, // You were missing this
+	}, &res)
+	if err != nil {
+		log.Printf("[WATCH] Failed to get VidraResource %s/%s: %v", namespace, name, err)
+		return
+	}
+	if res.Spec.Suspend {
+		log.Printf("[WATCH] VidraResource %s/%s is suspended, skipping reconcile trigger", res.Namespace, res.Name)
+		return
+	}
+	if res.Spec.Destination.DriftPolicy == infrahubv1alpha1.DriftPolicyIgnore {
+		log.Printf("[WATCH] VidraResource %s/%s ignores drift, skipping reconcile trigger", res.Namespace, res.Name)
+		return
+	}
+
+	if res.Spec.ReconciledAt.Time.Before(time.Now().Add(-2 * time.Second)) {
+		res.Spec.ReconciledAt = v1.Time{Time: time.Now()}
+		if err := r.Update(context.Background(), &res); err != nil {
+			log.Printf("[WATCH] Failed to update VidraResource %s/%s: %v", res.Namespace, res.Name, err)
+		} else {
+			log.Printf("[WATCH] Triggered reconcile of VidraResource %s/%s", res.Namespace, res.Name)
+		}
+	} else {
+		log.Printf("[WATCH] VidraResource %s/%s is already up-to-date, skipping reconcile trigger", res.Namespace, res.Name)
+	}
+}
+
+// watchCluster watches the GVRs on the remote cluster of the VidraResource. A VidraResource which moved to
+// another cluster no longer holds the watchers of its previous cluster.
+func (r *VidraResourceReconciler) watchCluster(name, cluster string, destClient client.Client, gvrs []schema.GroupVersionResource) error {
+	dynamicClient, err := r.DynamicMulticlusterFactory.GetDynamicClientFor(destClient)
+	if err != nil {
+		return err
+	}
+
+	r.watchMu.Lock()
+	previous := r.watchedClusters[name]
+	if r.watchedClusters == nil {
+		r.watchedClusters = map[string]string{}
+	}
+	r.watchedClusters[name] = cluster
+	r.watchMu.Unlock()
+	if previous != "" && previous != cluster {
+		r.stopUnwatchedCluster(previous)
+	}
+
+	r.DynamicWatcherFactory.StartWatchingClusterGVRs(cluster, dynamicClient, gvrs, r.handleRemoteResource)
+	return nil
+}
+
+// unwatchCluster releases the remote cluster watched for the VidraResource, its watchers are stopped once no
+// VidraResource targets the cluster any more
+func (r *VidraResourceReconciler) unwatchCluster(name string) {
+	r.watchMu.Lock()
+	cluster, ok := r.watchedClusters[name]
+	delete(r.watchedClusters, name)
+	r.watchMu.Unlock()
+
+	if ok {
+		r.stopUnwatchedCluster(cluster)
+	}
+}
+
+// stopUnwatchedCluster stops the watchers of the remote cluster if no VidraResource targets it any more
+func (r *VidraResourceReconciler) stopUnwatchedCluster(cluster string) {
+	r.watchMu.Lock()
+	defer r.watchMu.Unlock()
+	for _, watched := range r.watchedClusters {
+		if watched == cluster {
+			return
+		}
+	}
+	r.DynamicWatcherFactory.StopWatchingCluster(cluster)
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	"github.com/infrahub-operator/vidra/internal/adapter/k8s"
+	mock "github.com/infrahub-operator/vidra/internal/mocks"
+)
+
+var _ = Describe("Remote cluster watchers", func() {
+	var (
+		ctx            = context.Background()
+		fakeClient     client.Client
+		remoteClient   client.Client
+		clusterFactory *mock.MockDynamicMulticlusterFactory
+		watcherFactory *mock.MockDynamicWatcherFactory
+		reconciler     *VidraResourceReconciler
+		configMaps     = []schema.GroupVersionResource{{Version: "v1", Resource: "configmaps"}}
+	)
+
+	newResource := func(name string, suspend bool) *infrahubv1alpha1.VidraResource {
+		return &infrahubv1alpha1.VidraResource{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: infrahubv1alpha1.VidraResourceSpec{
+				Suspend:      suspend,
+				ReconciledAt: metav1.NewTime(time.Now().Add(-time.Hour)),
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(infrahubv1alpha1.AddToScheme(scheme)).To(Succeed())
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(newResource("web", false), newResource("db", false), newResource("paused", true)).Build()
+		remoteClient = fake.NewClientBuilder().Build()
+
+		mockCtrl := gomock.NewController(GinkgoT())
+		clusterFactory = mock.NewMockDynamicMulticlusterFactory(mockCtrl)
+		watcherFactory = mock.NewMockDynamicWatcherFactory(mockCtrl)
+		reconciler = &VidraResourceReconciler{
+			Client:                     fakeClient,
+			DynamicMulticlusterFactory: clusterFactory,
+			DynamicWatcherFactory:      watcherFactory,
+		}
+	})
+
+	It("should stop the watchers of a cluster once no VidraResource targets it", func() {
+		dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+		clusterFactory.EXPECT().GetDynamicClientFor(remoteClient).Return(dynamicClient, nil).Times(3)
+		watcherFactory.EXPECT().StartWatchingClusterGVRs("cluster/lab", dynamicClient, configMaps, gomock.Any()).Times(2)
+
+		Expect(reconciler.watchCluster("web", "cluster/lab", remoteClient, configMaps)).To(Succeed())
+		Expect(reconciler.watchCluster("db", "cluster/lab", remoteClient, configMaps)).To(Succeed())
+
+		By("moving a VidraResource to another cluster")
+		watcherFactory.EXPECT().StartWatchingClusterGVRs("cluster/prod", dynamicClient, configMaps, gomock.Any())
+		Expect(reconciler.watchCluster("db", "cluster/prod", remoteClient, configMaps)).To(Succeed())
+
+		By("keeping the cluster while a VidraResource still targets it")
+		reconciler.unwatchCluster("unknown")
+		watcherFactory.EXPECT().StopWatchingCluster("cluster/lab")
+		reconciler.unwatchCluster("web")
+		watcherFactory.EXPECT().StopWatchingCluster("cluster/prod")
+		reconciler.unwatchCluster("db")
+		Expect(reconciler.watchedClusters).To(BeEmpty())
+	})
+
+	It("should trigger the VidraResources of the owner annotation of a remote resource", func() {
+		obj := &unstructured.Unstructured{}
+		obj.SetNamespace("default")
+		obj.SetName("example")
+		obj.SetAnnotations(map[string]string{OwnerAnnotation: "web,paused,missing"})
+
+		reconciler.handleRemoteResource(obj, configMaps[0])
+
+		res := &infrahubv1alpha1.VidraResource{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "web"}, res)).To(Succeed())
+		Expect(res.Spec.ReconciledAt.Time).To(BeTemporally("~", time.Now(), 5*time.Second))
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "paused"}, res)).To(Succeed())
+		Expect(res.Spec.ReconciledAt.Time).To(BeTemporally("<", time.Now().Add(-time.Minute)))
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "db"}, res)).To(Succeed())
+		Expect(res.Spec.ReconciledAt.Time).To(BeTemporally("<", time.Now().Add(-time.Minute)))
+	})
+
+	It("should evict the server and trigger the VidraResources of a changed kubeconfig Secret", func() {
+		withServer := func(name, server string) *infrahubv1alpha1.VidraResource {
+			res := newResource(name, false)
+			res.Spec.Destination.Server = server
+			return res
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(fakeClient.Scheme()).WithObjects(
+			withServer("web", "https://my-cluster.example.com:6443"),
+			withServer("db", "https://other.example.com:6443"),
+			newResource("local", false),
+		).Build()
+		reconciler.Client = fakeClient
+
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-kubeconfig",
+			Namespace: "vidra-system",
+			Labels:    map[string]string{k8s.ClusterKubeconfigLabel: "my-cluster.example.com"},
+		}}
+		clusterFactory.EXPECT().EvictServer("my-cluster.example.com")
+
+		Expect(reconciler.resourcesUsingKubeconfig(ctx, secret)).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "web"}},
+		))
+	})
+})
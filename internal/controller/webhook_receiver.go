@@ -0,0 +1,258 @@
+package controller
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// WebhookPath is the path the webhook receiver accepts Infrahub webhooks on
+	WebhookPath = "/webhook/infrahub"
+	// WebhookSecretKey is the key of the shared webhook secret in the Infrahub credentials Secret
+	WebhookSecretKey = "webhookSecret"
+
+	// maxWebhookBodySize limits the size of an accepted webhook payload
+	maxWebhookBodySize = 1 << 20
+	// webhookTimestampTolerance is the maximum age of a webhook, older ones are rejected to prevent replays
+	webhookTimestampTolerance = 5 * time.Minute
+	// webhookEnqueueTimeout bounds how long a webhook waits for the sync queue before it is answered with 503
+	webhookEnqueueTimeout = 5 * time.Second
+
+	// SyncRequestedAnnotation is set on an InfrahubSync by replicas which are not the leader to request an immediate sync
+	SyncRequestedAnnotation = "vidra.infrahub.operators.com/sync-requested-at"
+)
+
+// infrahubWebhookEvent is the payload of an Infrahub event webhook
+type infrahubWebhookEvent struct {
+	// Event is the type of the event, e.g. infrahub.artifact.updated or infrahub.branch.merged
+	Event string `json:"event"`
+	// Branch is the branch the event happened on
+	Branch string `json:"branch"`
+	Data   struct {
+		// ArtifactName is the name of the artifact of artifact events, if provided
+		ArtifactName string `json:"artifact_name"`
+		// ArtifactDefinition is the name of the artifact definition of artifact events, if provided
+		ArtifactDefinition string `json:"artifact_definition"`
+	} `json:"data"`
+}
+
+// WebhookReceiver accepts Infrahub artifact and branch event webhooks and triggers an immediate sync
+// of the matching InfrahubSync resources. Webhooks are signed following the Standard Webhooks specification
+// with the webhookSecret of the credentials Secret of the Infrahub instance, which also identifies the instance.
+// The receiver runs on every replica: the leader enqueues the syncs directly, the other replicas set the
+// SyncRequestedAnnotation on the syncs, which the controller of the leader reconciles.
+type WebhookReceiver struct {
+	client.Client
+	// BindAddress is the address the receiver listens on
+	BindAddress string
+	// Events receives the InfrahubSync resources to reconcile
+	Events chan<- event.GenericEvent
+	// Elected is closed once this replica is the leader, the replica always leads if it is nil
+	Elected <-chan struct{}
+}
+
+// NeedLeaderElection reports that the receiver runs on all replicas, as the Service routes webhooks to any of them
+func (w *WebhookReceiver) NeedLeaderElection() bool {
+	return false
+}
+
+// Start runs the webhook receiver until the context is cancelled
+func (w *WebhookReceiver) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(WebhookPath, w)
+	server := &http.Server{
+		Addr:              w.BindAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = server.Shutdown(context.Background())
+	}()
+
+	log.FromContext(ctx).Info("Starting Infrahub webhook receiver", "address", w.BindAddress, "path", WebhookPath)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// ServeHTTP verifies the signature of the webhook and enqueues the matching InfrahubSync resources
+func (w *WebhookReceiver) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	logger := log.FromContext(ctx)
+	if req.Method != http.MethodPost {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxWebhookBodySize))
+	if err != nil {
+		http.Error(rw, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	host, err := w.authenticate(ctx, req.Header, body)
+	if err != nil {
+		logger.Info("Rejected Infrahub webhook", "reason", err.Error())
+		http.Error(rw, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var ev infrahubWebhookEvent
+	if err := json.Unmarshal(body, &ev); err != nil {
+		http.Error(rw, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	if !strings.HasPrefix(ev.Event, "infrahub.artifact.") && !strings.HasPrefix(ev.Event, "infrahub.branch.") {
+		logger.Info("Ignoring Infrahub webhook", "event", ev.Event)
+		rw.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	var syncs infrahubv1alpha1.InfrahubSyncList
+	if err := w.List(ctx, &syncs); err != nil {
+		logger.Error(err, "Failed to list InfrahubSyncs")
+		http.Error(rw, "failed to list InfrahubSyncs", http.StatusInternalServerError)
+		return
+	}
+
+	triggered := 0
+	for i := range syncs.Items {
+		infrahubSync := &syncs.Items[i]
+		if infrahubSync.Spec.Suspend || !matchesWebhookEvent(infrahubSync, host, ev) {
+			continue
+		}
+		if err := w.trigger(ctx, infrahubSync); err != nil {
+			logger.Error(err, "Failed to trigger InfrahubSync", "name", infrahubSync.Name)
+			http.Error(rw, "failed to trigger InfrahubSync", http.StatusServiceUnavailable)
+			return
+		}
+		triggered++
+	}
+
+	logger.Info("Received Infrahub webhook", "event", ev.Event, "branch", ev.Branch, "host", host, "triggered", triggered)
+	rw.WriteHeader(http.StatusAccepted)
+	_, _ = fmt.Fprintf(rw, "triggered %d InfrahubSyncs\n", triggered)
+}
+
+// trigger requests an immediate sync of the InfrahubSync. The leader enqueues it, waiting at most
+// webhookEnqueueTimeout for the queue, other replicas request it through the SyncRequestedAnnotation.
+func (w *WebhookReceiver) trigger(ctx context.Context, infrahubSync *infrahubv1alpha1.InfrahubSync) error {
+	if !w.isLeader() {
+		patch := client.MergeFrom(infrahubSync.DeepCopy())
+		annotations := infrahubSync.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[SyncRequestedAnnotation] = time.Now().UTC().Format(time.RFC3339Nano)
+		infrahubSync.SetAnnotations(annotations)
+		return w.Patch(ctx, infrahubSync, patch)
+	}
+
+	timer := time.NewTimer(webhookEnqueueTimeout)
+	defer timer.Stop()
+	select {
+	case w.Events <- event.GenericEvent{Object: infrahubSync}:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("sync queue is full")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// isLeader reports whether this replica runs the controllers and consumes the events
+func (w *WebhookReceiver) isLeader() bool {
+	if w.Elected == nil {
+		return true
+	}
+	select {
+	case <-w.Elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// authenticate verifies the signature against the webhook secrets of all Infrahub credentials Secrets
+// and returns the host of the Infrahub instance the matching Secret belongs to
+func (w *WebhookReceiver) authenticate(ctx context.Context, header http.Header, body []byte) (string, error) {
+	id := header.Get("webhook-id")
+	timestamp := header.Get("webhook-timestamp")
+	signatures := header.Get("webhook-signature")
+	if id == "" || timestamp == "" || signatures == "" {
+		return "", fmt.Errorf("missing webhook headers")
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid webhook timestamp: %w", err)
+	}
+	if age := time.Since(time.Unix(seconds, 0)); age > webhookTimestampTolerance || age < -webhookTimestampTolerance {
+		return "", fmt.Errorf("webhook timestamp outside of tolerance")
+	}
+
+	var secrets v1.SecretList
+	if err := w.List(ctx, &secrets, client.HasLabels{"infrahub-api-url"}); err != nil {
+		return "", fmt.Errorf("failed to list credentials Secrets: %w", err)
+	}
+	for _, secret := range secrets.Items {
+		key := secret.Data[WebhookSecretKey]
+		if len(key) == 0 {
+			continue
+		}
+		expected := signWebhook(key, id, timestamp, body)
+		for _, signature := range strings.Fields(signatures) {
+			if hmac.Equal([]byte(signature), []byte(expected)) {
+				return secret.Labels["infrahub-api-url"], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no webhook secret matches the signature")
+}
+
+// signWebhook returns the Standard Webhooks signature (v1,<base64 HMAC-SHA256>) of the payload
+func signWebhook(key []byte, id, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id + "." + timestamp + "."))
+	mac.Write(body)
+	return "v1," + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// matchesWebhookEvent reports whether the event of the Infrahub instance affects the sync.
+// Merged branches change the target branches, so merge events trigger the syncs of all branches.
+func matchesWebhookEvent(infrahubSync *infrahubv1alpha1.InfrahubSync, host string, ev infrahubWebhookEvent) bool {
+	apiURL, err := url.Parse(infrahubSync.Spec.Source.InfrahubAPIURL)
+	if err != nil || apiURL.Hostname() != host {
+		return false
+	}
+	if ev.Event != "infrahub.branch.merged" && ev.Branch != "" && ev.Branch != infrahubSync.Spec.Source.TargetBranch {
+		return false
+	}
+	selector := infrahubSync.Spec.Source.Selector
+	if selector == nil {
+		// Syncs without a selector match the artifact by its name
+		name := infrahubSync.Spec.Source.ArtifactName
+		return ev.Data.ArtifactName == "" || name == "" || ev.Data.ArtifactName == name
+	}
+	// Syncs with a selector match the definition of the selector, or every definition if none is selected
+	return ev.Data.ArtifactDefinition == "" || selector.ArtifactDefinition == "" ||
+		ev.Data.ArtifactDefinition == selector.ArtifactDefinition
+}
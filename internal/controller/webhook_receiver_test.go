@@ -0,0 +1,232 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+)
+
+var _ = Describe("Webhook receiver", func() {
+	const webhookSecret = "shared-secret"
+
+	var (
+		server     *httptest.Server
+		receiver   *WebhookReceiver
+		fakeClient client.Client
+		events     chan event.GenericEvent
+	)
+
+	newSync := func(name, apiURL, branch, artifact string, suspend bool) *infrahubv1alpha1.InfrahubSync {
+		return &infrahubv1alpha1.InfrahubSync{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: infrahubv1alpha1.InfrahubSyncSpec{
+				Source: infrahubv1alpha1.InfrahubSyncSource{
+					InfrahubAPIURL: apiURL,
+					TargetBranch:   branch,
+					ArtifactName:   artifact,
+				},
+				Suspend: suspend,
+			},
+		}
+	}
+
+	newRequest := func(ctx context.Context, payload string, sign func(id, timestamp string, body []byte) string) *http.Request {
+		id := "msg_1"
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+WebhookPath, bytes.NewBufferString(payload))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("webhook-id", id)
+		req.Header.Set("webhook-timestamp", timestamp)
+		req.Header.Set("webhook-signature", sign(id, timestamp, []byte(payload)))
+		return req
+	}
+	post := func(payload string, sign func(id, timestamp string, body []byte) string) *http.Response {
+		resp, err := http.DefaultClient.Do(newRequest(context.Background(), payload, sign))
+		Expect(err).NotTo(HaveOccurred())
+		return resp
+	}
+	validSignature := func(id, timestamp string, body []byte) string {
+		return signWebhook([]byte(webhookSecret), id, timestamp, body)
+	}
+	triggered := func() []string {
+		var names []string
+		for {
+			select {
+			case ev := <-events:
+				names = append(names, ev.Object.GetName())
+			default:
+				return names
+			}
+		}
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(infrahubv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "infrahub-credentials",
+				Namespace: "vidra-system",
+				Labels:    map[string]string{"infrahub-api-url": "infrahub.example.com"},
+			},
+			Data: map[string][]byte{WebhookSecretKey: []byte(webhookSecret)},
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			secret,
+			newSync("webserver-main", "https://infrahub.example.com", "main", "Webserver_Manifest", false),
+			newSync("webserver-dev", "https://infrahub.example.com", "dev", "Webserver_Manifest", false),
+			newSync("vm-main", "https://infrahub.example.com", "main", "VM_Manifest", false),
+			newSync("suspended-main", "https://infrahub.example.com", "main", "Webserver_Manifest", true),
+			newSync("other-instance", "https://other.example.com", "main", "Webserver_Manifest", false),
+		).Build()
+
+		events = make(chan event.GenericEvent, 10)
+		receiver = &WebhookReceiver{Client: fakeClient, Events: events}
+		server = httptest.NewServer(receiver)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should enqueue the syncs matching the instance, branch and artifact name of an artifact event", func() {
+		resp := post(`{"event": "infrahub.artifact.updated", "branch": "main", `+
+			`"data": {"artifact_name": "Webserver_Manifest", "artifact_definition": "Webserver_Definition"}}`, validSignature)
+		defer resp.Body.Close() //nolint:errcheck
+		Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+		body, _ := io.ReadAll(resp.Body)
+		Expect(string(body)).To(ContainSubstring("triggered 1 InfrahubSyncs"))
+		Expect(triggered()).To(ConsistOf("webserver-main"))
+	})
+
+	It("should match syncs without a selector by the artifact name, not the artifact definition", func() {
+		ev := infrahubWebhookEvent{Event: "infrahub.artifact.updated", Branch: "main"}
+		ev.Data.ArtifactName = "Webserver_Manifest"
+		ev.Data.ArtifactDefinition = "Webserver_Definition"
+
+		Expect(matchesWebhookEvent(newSync("webserver-main", "https://infrahub.example.com", "main", "Webserver_Manifest", false),
+			"infrahub.example.com", ev)).To(BeTrue())
+		Expect(matchesWebhookEvent(newSync("vm-main", "https://infrahub.example.com", "main", "VM_Manifest", false),
+			"infrahub.example.com", ev)).To(BeFalse())
+	})
+
+	It("should enqueue all syncs of the branch if the artifact is not part of the event", func() {
+		resp := post(`{"event": "infrahub.artifact.created", "branch": "main"}`, validSignature)
+		defer resp.Body.Close() //nolint:errcheck
+		Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+		Expect(triggered()).To(ConsistOf("webserver-main", "vm-main"))
+	})
+
+	It("should match artifact events by the artifact definition of a selector", func() {
+		ev := infrahubWebhookEvent{Event: "infrahub.artifact.updated", Branch: "main"}
+		ev.Data.ArtifactName = "leaf-1-config"
+		ev.Data.ArtifactDefinition = "Leaf_Config"
+		sync := newSync("leafs-main", "https://infrahub.example.com", "main", "", false)
+
+		sync.Spec.Source.Selector = &infrahubv1alpha1.ArtifactSelector{ArtifactDefinition: "Leaf_Config"}
+		Expect(matchesWebhookEvent(sync, "infrahub.example.com", ev)).To(BeTrue())
+		sync.Spec.Source.Selector = &infrahubv1alpha1.ArtifactSelector{ArtifactDefinition: "Spine_Config"}
+		Expect(matchesWebhookEvent(sync, "infrahub.example.com", ev)).To(BeFalse())
+		sync.Spec.Source.Selector = &infrahubv1alpha1.ArtifactSelector{TargetGroup: "leafs"}
+		Expect(matchesWebhookEvent(sync, "infrahub.example.com", ev)).To(BeTrue())
+	})
+
+	It("should enqueue the syncs of all branches if a branch is merged", func() {
+		resp := post(`{"event": "infrahub.branch.merged", "branch": "dev"}`, validSignature)
+		defer resp.Body.Close() //nolint:errcheck
+		Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+		Expect(triggered()).To(ConsistOf("webserver-main", "webserver-dev", "vm-main"))
+	})
+
+	It("should ignore other events", func() {
+		resp := post(`{"event": "infrahub.node.updated", "branch": "main"}`, validSignature)
+		defer resp.Body.Close() //nolint:errcheck
+		Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+		Expect(triggered()).To(BeEmpty())
+	})
+
+	It("should reject webhooks with an invalid signature", func() {
+		resp := post(`{"event": "infrahub.artifact.updated", "branch": "main"}`, func(id, timestamp string, body []byte) string {
+			return signWebhook([]byte("wrong-secret"), id, timestamp, body)
+		})
+		defer resp.Body.Close() //nolint:errcheck
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		Expect(triggered()).To(BeEmpty())
+	})
+
+	It("should reject webhooks with an outdated timestamp", func() {
+		resp := post(`{"event": "infrahub.artifact.updated", "branch": "main"}`, func(id, _ string, body []byte) string {
+			return signWebhook([]byte(webhookSecret), id, "1000", body)
+		})
+		defer resp.Body.Close() //nolint:errcheck
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should only accept POST requests", func() {
+		resp, err := http.Get(server.URL + WebhookPath)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close() //nolint:errcheck
+		Expect(resp.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+	})
+
+	It("should run on all replicas", func() {
+		Expect(receiver.NeedLeaderElection()).To(BeFalse())
+	})
+
+	It("should request the sync through an annotation if the replica is not the leader", func() {
+		receiver.Elected = make(chan struct{})
+
+		resp := post(`{"event": "infrahub.artifact.updated", "branch": "main", "data": {"artifact_name": "VM_Manifest"}}`, validSignature)
+		defer resp.Body.Close() //nolint:errcheck
+		Expect(resp.StatusCode).To(Equal(http.StatusAccepted))
+		Expect(triggered()).To(BeEmpty())
+
+		infrahubSync := &infrahubv1alpha1.InfrahubSync{}
+		Expect(fakeClient.Get(context.Background(), types.NamespacedName{Name: "vm-main"}, infrahubSync)).To(Succeed())
+		Expect(infrahubSync.Annotations).To(HaveKey(SyncRequestedAnnotation))
+		Expect(fakeClient.Get(context.Background(), types.NamespacedName{Name: "webserver-main"}, infrahubSync)).To(Succeed())
+		Expect(infrahubSync.Annotations).NotTo(HaveKey(SyncRequestedAnnotation))
+	})
+
+	It("should answer with 503 instead of blocking if the sync queue is not read", func() {
+		receiver.Events = make(chan event.GenericEvent)
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		recorder := httptest.NewRecorder()
+		receiver.ServeHTTP(recorder, newRequest(ctx, `{"event": "infrahub.branch.merged", "branch": "main"}`, validSignature))
+		Expect(recorder.Code).To(Equal(http.StatusServiceUnavailable))
+	})
+
+	It("should pass updates of the sync requested annotation to the InfrahubSync controller", func() {
+		requested := newSync("vm-main", "https://infrahub.example.com", "main", "VM_Manifest", false)
+		requested.Annotations = map[string]string{SyncRequestedAnnotation: time.Now().Format(time.RFC3339Nano)}
+		other := newSync("vm-main", "https://infrahub.example.com", "main", "VM_Manifest", false)
+		other.Annotations = map[string]string{"note": "changed"}
+
+		p := syncRequestedPredicate()
+		Expect(p.Update(event.UpdateEvent{ObjectOld: newSync("vm-main", "", "", "", false), ObjectNew: requested})).To(BeTrue())
+		Expect(p.Update(event.UpdateEvent{ObjectOld: newSync("vm-main", "", "", "", false), ObjectNew: other})).To(BeFalse())
+		Expect(p.Update(event.UpdateEvent{ObjectOld: requested, ObjectNew: requested.DeepCopy()})).To(BeFalse())
+	})
+})
@@ -0,0 +1,11 @@
+package domain
+
+import (
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// HealthChecker assesses the health of a live resource in the destination cluster.
+type HealthChecker interface {
+	Check(obj *unstructured.Unstructured) (infrahubv1alpha1.HealthStatus, string)
+}
@@ -1,12 +1,19 @@
 // internal/domain/infrahub.go
 package domain
 
-import "io"
+import (
+	"context"
+	"io"
+)
 
 // InfrahubClient defines methods for interacting with Infrahub.
 type InfrahubClient interface {
-	Login(apiURL, username, password string) (string, error)
-	RunQuery(queryName string, apiURL string, artifactName string, targetBranche string, targetDate string, token string) (*[]Artifact, error)
+	Login(ctx context.Context, apiURL string, credentials Credentials) (Token, error)
+	RunQuery(ctx context.Context, queryName string, apiURL string, artifactName string, targetBranche string, targetDate string, token Token) (*[]Artifact, error)
+	// QueryArtifacts fetches all artifacts matching the query with an inline GraphQL query, page by page
+	QueryArtifacts(ctx context.Context, apiURL string, query ArtifactQuery, token Token) (*[]Artifact, error)
 	// BuildURL(apiURL, path string, queryParams, headers map[string]string) (string, error)
-	DownloadArtifact(apiURL string, artifactID string, targetBranche string, targetDate string, token string) (io.Reader, error)
+	DownloadArtifact(ctx context.Context, apiURL string, artifactID string, targetBranche string, targetDate string, token Token) (io.Reader, error)
+	// WithConnection returns a client using the TLS and proxy settings of the connection, sharing the token cache
+	WithConnection(connection Connection) (InfrahubClient, error)
 }
@@ -4,4 +4,53 @@ type Artifact struct {
 	ID        string
 	StorageID string
 	Checksum  string
+	// Name is only returned by the GraphQL query
+	Name string
+}
+
+// Credentials authenticate against Infrahub, an APIToken takes precedence over Username and Password
+type Credentials struct {
+	Username string
+	Password string
+	APIToken string
+}
+
+// Token authenticates requests to Infrahub
+type Token struct {
+	// Value is a JWT access token from a login or an API token
+	Value string
+	// APIToken is set if Value is an API token, sent as X-INFRAHUB-KEY instead of a Bearer token
+	APIToken bool
+}
+
+// Connection configures the HTTP connection to an Infrahub instance
+type Connection struct {
+	// CABundle contains PEM encoded CA certificates trusted in addition to the system CAs
+	CABundle []byte
+	// ClientCert and ClientKey are the PEM encoded client certificate and key for mutual TLS
+	ClientCert []byte
+	ClientKey  []byte
+	// InsecureSkipVerify disables the verification of the Infrahub certificate
+	InsecureSkipVerify bool
+	// ProxyURL is the HTTP(S) proxy, the proxy of the environment is used if it is empty
+	ProxyURL string
+}
+
+// ArtifactQuery filters the artifacts of an inline GraphQL query, empty filters are not applied
+type ArtifactQuery struct {
+	// Name of the artifacts (name__value)
+	Name string
+	// Branch and At select the branch and the point in time to query
+	Branch string
+	At     string
+	// ObjectIDs are the IDs of the objects the artifacts are generated for (object__ids)
+	ObjectIDs []string
+	// Statuses of the artifacts (status__values)
+	Statuses []string
+	// Definition is the name of the artifact definition (definition__name__value)
+	Definition string
+	// TargetGroup limits the artifacts to the members of the group (CoreStandardGroup) with this name
+	TargetGroup string
+	// PageSize is the number of artifacts fetched per request
+	PageSize int
 }
@@ -3,9 +3,22 @@ package domain
 import (
 	"context"
 
+	infrahubv1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
+	"k8s.io/client-go/dynamic"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type DynamicMulticlusterFactory interface {
+	// GetCachedClientFor returns the client of the cluster whose kubeconfig Secret is labeled with the host of the server URL
 	GetCachedClientFor(ctx context.Context, serverURL string, k8sClient client.Client) (client.Client, error)
+	// GetClientForCluster returns the client of the VidraCluster, rebuilt once its spec or credentials change
+	GetClientForCluster(ctx context.Context, cluster *infrahubv1alpha1.VidraCluster, k8sClient client.Client) (client.Client, error)
+	// ServerVersion returns the Kubernetes version reported by the API server of the VidraCluster
+	ServerVersion(ctx context.Context, cluster *infrahubv1alpha1.VidraCluster, k8sClient client.Client) (string, error)
+	// EvictCluster drops the cached client of the VidraCluster
+	EvictCluster(name string)
+	// EvictServer drops the cached clients of the servers with the host
+	EvictServer(host string)
+	// GetDynamicClientFor returns the dynamic client of the cluster of a client returned by the factory
+	GetDynamicClientFor(c client.Client) (dynamic.Interface, error)
 }
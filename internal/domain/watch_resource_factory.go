@@ -10,4 +10,8 @@ type ResourceCallback func(obj *unstructured.Unstructured, gvr schema.GroupVersi
 
 type DynamicWatcherFactory interface {
 	StartWatchingGVRs(dynamicClient dynamic.Interface, gvrs []schema.GroupVersionResource, onEvent ResourceCallback)
+	// StartWatchingClusterGVRs watches the GVRs on the remote cluster with the dynamic client of that cluster
+	StartWatchingClusterGVRs(cluster string, dynamicClient dynamic.Interface, gvrs []schema.GroupVersionResource, onEvent ResourceCallback)
+	// StopWatchingCluster stops all watchers of the remote cluster
+	StopWatchingCluster(cluster string)
 }
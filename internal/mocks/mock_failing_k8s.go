@@ -9,6 +9,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/scale/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -77,6 +78,10 @@ func (f *FailingUpdateClient) List(ctx context.Context, list client.ObjectList,
 }
 
 func (f *FailingUpdateClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	// Server-side apply is a patch as well, but can be failed separately
+	if patch.Type() == types.ApplyPatchType && f.shouldFail("Apply") {
+		return fmt.Errorf("%s: Apply - %v", f.UpdateErrMsg, obj)
+	}
 	if f.shouldFail("Patch") {
 		return fmt.Errorf("%s: Patch - %v", f.UpdateErrMsg, obj)
 	}
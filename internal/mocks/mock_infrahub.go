@@ -10,6 +10,7 @@
 package mock
 
 import (
+	context "context"
 	io "io"
 	reflect "reflect"
 
@@ -42,46 +43,76 @@ func (m *MockInfrahubClient) EXPECT() *MockInfrahubClientMockRecorder {
 }
 
 // DownloadArtifact mocks base method.
-func (m *MockInfrahubClient) DownloadArtifact(apiURL, artifactID, targetBranche, targetDate, token string) (io.Reader, error) {
+func (m *MockInfrahubClient) DownloadArtifact(ctx context.Context, apiURL, artifactID, targetBranche, targetDate string, token domain.Token) (io.Reader, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DownloadArtifact", apiURL, artifactID, targetBranche, targetDate, token)
+	ret := m.ctrl.Call(m, "DownloadArtifact", ctx, apiURL, artifactID, targetBranche, targetDate, token)
 	ret0, _ := ret[0].(io.Reader)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DownloadArtifact indicates an expected call of DownloadArtifact.
-func (mr *MockInfrahubClientMockRecorder) DownloadArtifact(apiURL, artifactID, targetBranche, targetDate, token any) *gomock.Call {
+func (mr *MockInfrahubClientMockRecorder) DownloadArtifact(ctx, apiURL, artifactID, targetBranche, targetDate, token any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadArtifact", reflect.TypeOf((*MockInfrahubClient)(nil).DownloadArtifact), apiURL, artifactID, targetBranche, targetDate, token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadArtifact", reflect.TypeOf((*MockInfrahubClient)(nil).DownloadArtifact), ctx, apiURL, artifactID, targetBranche, targetDate, token)
 }
 
 // Login mocks base method.
-func (m *MockInfrahubClient) Login(apiURL, username, password string) (string, error) {
+func (m *MockInfrahubClient) Login(ctx context.Context, apiURL string, credentials domain.Credentials) (domain.Token, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", apiURL, username, password)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "Login", ctx, apiURL, credentials)
+	ret0, _ := ret[0].(domain.Token)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockInfrahubClientMockRecorder) Login(apiURL, username, password any) *gomock.Call {
+func (mr *MockInfrahubClientMockRecorder) Login(ctx, apiURL, credentials any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockInfrahubClient)(nil).Login), apiURL, username, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockInfrahubClient)(nil).Login), ctx, apiURL, credentials)
+}
+
+// QueryArtifacts mocks base method.
+func (m *MockInfrahubClient) QueryArtifacts(ctx context.Context, apiURL string, query domain.ArtifactQuery, token domain.Token) (*[]domain.Artifact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryArtifacts", ctx, apiURL, query, token)
+	ret0, _ := ret[0].(*[]domain.Artifact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryArtifacts indicates an expected call of QueryArtifacts.
+func (mr *MockInfrahubClientMockRecorder) QueryArtifacts(ctx, apiURL, query, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryArtifacts", reflect.TypeOf((*MockInfrahubClient)(nil).QueryArtifacts), ctx, apiURL, query, token)
 }
 
 // RunQuery mocks base method.
-func (m *MockInfrahubClient) RunQuery(queryName, apiURL, artifactName, targetBranche, targetDate, token string) (*[]domain.Artifact, error) {
+func (m *MockInfrahubClient) RunQuery(ctx context.Context, queryName, apiURL, artifactName, targetBranche, targetDate string, token domain.Token) (*[]domain.Artifact, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RunQuery", queryName, apiURL, artifactName, targetBranche, targetDate, token)
+	ret := m.ctrl.Call(m, "RunQuery", ctx, queryName, apiURL, artifactName, targetBranche, targetDate, token)
 	ret0, _ := ret[0].(*[]domain.Artifact)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RunQuery indicates an expected call of RunQuery.
-func (mr *MockInfrahubClientMockRecorder) RunQuery(queryName, apiURL, artifactName, targetBranche, targetDate, token any) *gomock.Call {
+func (mr *MockInfrahubClientMockRecorder) RunQuery(ctx, queryName, apiURL, artifactName, targetBranche, targetDate, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunQuery", reflect.TypeOf((*MockInfrahubClient)(nil).RunQuery), ctx, queryName, apiURL, artifactName, targetBranche, targetDate, token)
+}
+
+// WithConnection mocks base method.
+func (m *MockInfrahubClient) WithConnection(connection domain.Connection) (domain.InfrahubClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithConnection", connection)
+	ret0, _ := ret[0].(domain.InfrahubClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WithConnection indicates an expected call of WithConnection.
+func (mr *MockInfrahubClientMockRecorder) WithConnection(connection any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunQuery", reflect.TypeOf((*MockInfrahubClient)(nil).RunQuery), queryName, apiURL, artifactName, targetBranche, targetDate, token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithConnection", reflect.TypeOf((*MockInfrahubClient)(nil).WithConnection), connection)
 }
@@ -13,7 +13,9 @@ import (
 	context "context"
 	reflect "reflect"
 
+	v1alpha1 "github.com/infrahub-operator/vidra/api/v1alpha1"
 	gomock "go.uber.org/mock/gomock"
+	dynamic "k8s.io/client-go/dynamic"
 	client "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -41,6 +43,30 @@ func (m *MockDynamicMulticlusterFactory) EXPECT() *MockDynamicMulticlusterFactor
 	return m.recorder
 }
 
+// EvictCluster mocks base method.
+func (m *MockDynamicMulticlusterFactory) EvictCluster(name string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "EvictCluster", name)
+}
+
+// EvictCluster indicates an expected call of EvictCluster.
+func (mr *MockDynamicMulticlusterFactoryMockRecorder) EvictCluster(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvictCluster", reflect.TypeOf((*MockDynamicMulticlusterFactory)(nil).EvictCluster), name)
+}
+
+// EvictServer mocks base method.
+func (m *MockDynamicMulticlusterFactory) EvictServer(host string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "EvictServer", host)
+}
+
+// EvictServer indicates an expected call of EvictServer.
+func (mr *MockDynamicMulticlusterFactoryMockRecorder) EvictServer(host any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvictServer", reflect.TypeOf((*MockDynamicMulticlusterFactory)(nil).EvictServer), host)
+}
+
 // GetCachedClientFor mocks base method.
 func (m *MockDynamicMulticlusterFactory) GetCachedClientFor(ctx context.Context, serverURL string, k8sClient client.Client) (client.Client, error) {
 	m.ctrl.T.Helper()
@@ -55,3 +81,48 @@ func (mr *MockDynamicMulticlusterFactoryMockRecorder) GetCachedClientFor(ctx, se
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCachedClientFor", reflect.TypeOf((*MockDynamicMulticlusterFactory)(nil).GetCachedClientFor), ctx, serverURL, k8sClient)
 }
+
+// GetClientForCluster mocks base method.
+func (m *MockDynamicMulticlusterFactory) GetClientForCluster(ctx context.Context, cluster *v1alpha1.VidraCluster, k8sClient client.Client) (client.Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClientForCluster", ctx, cluster, k8sClient)
+	ret0, _ := ret[0].(client.Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClientForCluster indicates an expected call of GetClientForCluster.
+func (mr *MockDynamicMulticlusterFactoryMockRecorder) GetClientForCluster(ctx, cluster, k8sClient any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClientForCluster", reflect.TypeOf((*MockDynamicMulticlusterFactory)(nil).GetClientForCluster), ctx, cluster, k8sClient)
+}
+
+// GetDynamicClientFor mocks base method.
+func (m *MockDynamicMulticlusterFactory) GetDynamicClientFor(c client.Client) (dynamic.Interface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDynamicClientFor", c)
+	ret0, _ := ret[0].(dynamic.Interface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDynamicClientFor indicates an expected call of GetDynamicClientFor.
+func (mr *MockDynamicMulticlusterFactoryMockRecorder) GetDynamicClientFor(c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDynamicClientFor", reflect.TypeOf((*MockDynamicMulticlusterFactory)(nil).GetDynamicClientFor), c)
+}
+
+// ServerVersion mocks base method.
+func (m *MockDynamicMulticlusterFactory) ServerVersion(ctx context.Context, cluster *v1alpha1.VidraCluster, k8sClient client.Client) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServerVersion", ctx, cluster, k8sClient)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ServerVersion indicates an expected call of ServerVersion.
+func (mr *MockDynamicMulticlusterFactoryMockRecorder) ServerVersion(ctx, cluster, k8sClient any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServerVersion", reflect.TypeOf((*MockDynamicMulticlusterFactory)(nil).ServerVersion), ctx, cluster, k8sClient)
+}
@@ -42,6 +42,18 @@ func (m *MockDynamicWatcherFactory) EXPECT() *MockDynamicWatcherFactoryMockRecor
 	return m.recorder
 }
 
+// StartWatchingClusterGVRs mocks base method.
+func (m *MockDynamicWatcherFactory) StartWatchingClusterGVRs(cluster string, dynamicClient dynamic.Interface, gvrs []schema.GroupVersionResource, onEvent domain.ResourceCallback) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "StartWatchingClusterGVRs", cluster, dynamicClient, gvrs, onEvent)
+}
+
+// StartWatchingClusterGVRs indicates an expected call of StartWatchingClusterGVRs.
+func (mr *MockDynamicWatcherFactoryMockRecorder) StartWatchingClusterGVRs(cluster, dynamicClient, gvrs, onEvent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartWatchingClusterGVRs", reflect.TypeOf((*MockDynamicWatcherFactory)(nil).StartWatchingClusterGVRs), cluster, dynamicClient, gvrs, onEvent)
+}
+
 // StartWatchingGVRs mocks base method.
 func (m *MockDynamicWatcherFactory) StartWatchingGVRs(dynamicClient dynamic.Interface, gvrs []schema.GroupVersionResource, onEvent domain.ResourceCallback) {
 	m.ctrl.T.Helper()
@@ -53,3 +65,15 @@ func (mr *MockDynamicWatcherFactoryMockRecorder) StartWatchingGVRs(dynamicClient
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartWatchingGVRs", reflect.TypeOf((*MockDynamicWatcherFactory)(nil).StartWatchingGVRs), dynamicClient, gvrs, onEvent)
 }
+
+// StopWatchingCluster mocks base method.
+func (m *MockDynamicWatcherFactory) StopWatchingCluster(cluster string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "StopWatchingCluster", cluster)
+}
+
+// StopWatchingCluster indicates an expected call of StopWatchingCluster.
+func (mr *MockDynamicWatcherFactoryMockRecorder) StopWatchingCluster(cluster any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopWatchingCluster", reflect.TypeOf((*MockDynamicWatcherFactory)(nil).StopWatchingCluster), cluster)
+}
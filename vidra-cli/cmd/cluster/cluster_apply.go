@@ -10,10 +10,24 @@ import (
 	"k8s.io/client-go/tools/clientcmd/api"
 )
 
+var (
+	server string
+	token  string
+	caFile string
+)
+
 var applyCmd = &cobra.Command{
 	Use:   "apply <context>",
 	Short: "Apply the cluster kubeconfig secret for the vidra-operator",
+	Long: `Apply the cluster kubeconfig secret for the vidra-operator.
+
+With --token the argument is the name of the VidraCluster, and a token Secret and the VidraCluster
+are applied without reading the local kubeconfig.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if token != "" {
+			applyToken(cmd, args)
+			return
+		}
 		if len(args) == 0 {
 			fmt.Fprintln(os.Stderr, "Error: accepts 1 arg(s), received 0")
 			cmd.Usage()
@@ -41,6 +55,33 @@ var applyCmd = &cobra.Command{
 
 func init() {
 	applyCmd.Flags().StringVarP(&namespace, "namespace", "n", "vidra-system", "Kubernetes namespace for the secret (default: \"default\")")
+	applyCmd.Flags().StringVarP(&server, "server", "s", "", "API server URL of the cluster (required with --token)")
+	applyCmd.Flags().StringVarP(&token, "token", "t", "", "Bearer token for the cluster, used instead of a kubeconfig context")
+	applyCmd.Flags().StringVar(&caFile, "ca", "", "Path to the CA certificate of the API server (used with --token)")
+	applyCmd.MarkFlagsRequiredTogether("token", "server")
+}
+
+// applyToken applies the token Secret and the VidraCluster of the cluster named by the argument
+func applyToken(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Error: accepts 1 arg(s), received %d\n", len(args))
+		cmd.Usage()
+		os.Exit(1)
+	}
+	var caData []byte
+	if caFile != "" {
+		var err error
+		caData, err = os.ReadFile(caFile)
+		if err != nil {
+			errorHandler(fmt.Errorf("failed to read CA file: %w", err))
+			os.Exit(1)
+		}
+	}
+	clusterService := setup()
+	if err := clusterService.ApplyClusterTokenSecret(args[0], server, token, caData, namespace); err != nil {
+		errorHandler(err)
+		os.Exit(1)
+	}
 }
 
 func printCurrentKubeContext() {
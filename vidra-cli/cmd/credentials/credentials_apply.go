@@ -10,6 +10,7 @@ import (
 var (
 	username string
 	password string
+	token    string
 )
 
 var applyCmd = &cobra.Command{
@@ -19,13 +20,18 @@ var applyCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		url := args[0]
 
-		if username == "" || password == "" {
-			fmt.Println("Both --username and --password are required")
+		if token == "" && (username == "" || password == "") {
+			fmt.Println("Either --token or both --username and --password are required")
 			cmd.Usage()
 			return
 		}
 		credentialsService := setup()
-		err := credentialsService.ApplyCredentialsSecret(url, username, password, namespace)
+		var err error
+		if token != "" {
+			err = credentialsService.ApplyCredentialsTokenSecret(url, token, namespace)
+		} else {
+			err = credentialsService.ApplyCredentialsSecret(url, username, password, namespace)
+		}
 		if err != nil {
 			errorHandler(err)
 			os.Exit(1)
@@ -35,8 +41,9 @@ var applyCmd = &cobra.Command{
 
 func init() {
 	applyCmd.Flags().StringVarP(&namespace, "namespace", "n", "vidra-system", "Kubernetes namespace for the secret (default: \"vidra-system\")")
-	applyCmd.Flags().StringVarP(&username, "username", "u", "", "Infrahub username (required)")
-	applyCmd.Flags().StringVarP(&password, "password", "p", "", "Infrahub password (required)")
-	applyCmd.MarkFlagRequired("username")
-	applyCmd.MarkFlagRequired("password")
+	applyCmd.Flags().StringVarP(&username, "username", "u", "", "Infrahub username (required without --token)")
+	applyCmd.Flags().StringVarP(&password, "password", "p", "", "Infrahub password (required without --token)")
+	applyCmd.Flags().StringVarP(&token, "token", "t", "", "Infrahub API token, used instead of username and password")
+	applyCmd.MarkFlagsRequiredTogether("username", "password")
+	applyCmd.MarkFlagsMutuallyExclusive("token", "username")
 }
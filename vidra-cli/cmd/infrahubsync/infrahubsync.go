@@ -33,6 +33,8 @@ func init() {
 	InfrahubSyncCmd.AddCommand(getCmd)
 	InfrahubSyncCmd.AddCommand(listCmd)
 	InfrahubSyncCmd.AddCommand(deleteCmd)
+	InfrahubSyncCmd.AddCommand(suspendCmd)
+	InfrahubSyncCmd.AddCommand(resumeCmd)
 }
 
 var setupFn = setup
@@ -0,0 +1,33 @@
+package infrahubsync
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume <url>",
+	Short: "Resume a suspended InfrahubSync by URL",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		urlStr := args[0]
+		infrahubSyncService := setup()
+		if name == "" {
+			name = "infrahubsync-" + generateHash(urlStr, date, branche, artifact)
+		}
+		err := infrahubSyncService.ResumeInfrahubSync(namespace, name)
+		if err != nil {
+			errorHandler(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	resumeCmd.Flags().StringVarP(&namespace, "namespace", "n", "vidra-system", "Kubernetes namespace of the InfrahubSync (default: \"vidra-system\")")
+	resumeCmd.Flags().StringVarP(&name, "InfrahubSync name", "N", "", "Name of the InfrahubSync resource (optional, defaults to a generated name based on the URL)")
+	resumeCmd.Flags().StringVarP(&branche, "targetBranche", "b", "main", "Infrahub branche to sync to")
+	resumeCmd.Flags().StringVarP(&date, "targetDate", "d", "", "Date and time to sync with Infrahub (RFC3339 format) or relative format (e.g. 5m, 2h))")
+	resumeCmd.Flags().StringVarP(&artifact, "artifactName", "a", "", "Name of the artifact definition in Infrahub to sync to")
+}
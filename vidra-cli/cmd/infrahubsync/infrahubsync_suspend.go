@@ -0,0 +1,33 @@
+package infrahubsync
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var suspendCmd = &cobra.Command{
+	Use:   "suspend <url>",
+	Short: "Suspend a InfrahubSync by URL, Infrahub is not queried until it is resumed",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		urlStr := args[0]
+		infrahubSyncService := setup()
+		if name == "" {
+			name = "infrahubsync-" + generateHash(urlStr, date, branche, artifact)
+		}
+		err := infrahubSyncService.SuspendInfrahubSync(namespace, name)
+		if err != nil {
+			errorHandler(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	suspendCmd.Flags().StringVarP(&namespace, "namespace", "n", "vidra-system", "Kubernetes namespace of the InfrahubSync (default: \"vidra-system\")")
+	suspendCmd.Flags().StringVarP(&name, "InfrahubSync name", "N", "", "Name of the InfrahubSync resource (optional, defaults to a generated name based on the URL)")
+	suspendCmd.Flags().StringVarP(&branche, "targetBranche", "b", "main", "Infrahub branche to sync to")
+	suspendCmd.Flags().StringVarP(&date, "targetDate", "d", "", "Date and time to sync with Infrahub (RFC3339 format) or relative format (e.g. 5m, 2h))")
+	suspendCmd.Flags().StringVarP(&artifact, "artifactName", "a", "", "Name of the artifact definition in Infrahub to sync to")
+}
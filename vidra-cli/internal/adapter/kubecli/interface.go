@@ -8,6 +8,7 @@ type KubeCLI interface {
 	GetByName(ctx context.Context, resource, namespace, name string) ([]byte, error)
 	ListByLabel(ctx context.Context, resource, label string, outputFormat string) ([]byte, error)
 	Delete(ctx context.Context, resource, name, namespace string) error
+	Patch(ctx context.Context, resource, name, namespace, patch string) error
 	EncodeBase64(data string) string
 	Hash(data string) string
 	LabelFromURL(url string) (string, error)
@@ -77,6 +77,17 @@ func (k *kubectlCLI) Delete(ctx context.Context, resource, name, namespace strin
 	return cmd.Run()
 }
 
+func (k *kubectlCLI) Patch(ctx context.Context, resource, name, namespace, patch string) error {
+	ctx, cancel := setTimeoutIfNoDeadline(ctx, time.Minute)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "kubectl", "patch", resource, name, "-n", namespace, "--type", "merge", "-p", patch)
+
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
 func setTimeoutIfNoDeadline(ctx context.Context, time time.Duration) (context.Context, context.CancelFunc) {
 	if _, ok := ctx.Deadline(); !ok {
 		return context.WithTimeout(ctx, time)
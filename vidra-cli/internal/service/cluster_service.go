@@ -77,6 +77,26 @@ func (s *clusterService) ApplyClusterKubeConfigSecret(
 	return s.kubecli.ApplyYAML(context.Background(), secretYAML)
 }
 
+// ApplyClusterTokenSecret applies a Secret with the bearer token and CA of the cluster and a VidraCluster using it,
+// so no local kubeconfig context is needed
+func (s *clusterService) ApplyClusterTokenSecret(clusterName, server, token string, caData []byte, namespace string) error {
+	label, err := s.kubecli.LabelFromURL(server)
+	if err != nil {
+		return fmt.Errorf("invalid server URL: %w", err)
+	}
+
+	secretName := fmt.Sprintf("cluster-kubeconfig-%s", s.kubecli.Hash(clusterName))
+	data := fmt.Sprintf("  token: %s\n", s.kubecli.EncodeBase64(token))
+	if len(caData) > 0 {
+		data += fmt.Sprintf("  ca.crt: %s\n", s.kubecli.EncodeBase64(string(caData)))
+	}
+
+	yaml := generateClusterTokenSecretYAML(secretName, label, data, namespace) + "---\n" +
+		generateVidraClusterYAML(clusterName, server, secretName, namespace)
+	fmt.Println(yaml + "\n\n---\n")
+	return s.kubecli.ApplyYAML(context.Background(), yaml)
+}
+
 func (s *clusterService) ListClusterKubeConfigSecrets() error {
 	result, err := s.kubecli.ListByLabel(
 		context.Background(),
@@ -109,3 +129,30 @@ data:
   kubeconfig: %s
 `, clusterID, namespace, clusterLabel, encoded)
 }
+
+func generateClusterTokenSecretYAML(secretName, clusterLabel, data, namespace string) string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: Secret
+metadata:
+  name: %s
+  namespace: %s
+  labels:
+    cluster-kubeconfig: %s
+type: Opaque
+data:
+%s`, secretName, namespace, clusterLabel, data)
+}
+
+func generateVidraClusterYAML(clusterName, server, secretName, namespace string) string {
+	return fmt.Sprintf(`apiVersion: infrahub.operators.com/v1alpha1
+kind: VidraCluster
+metadata:
+  name: %s
+spec:
+  server: %s
+  credentialsType: Token
+  credentialsSecretRef:
+    name: %s
+    namespace: %s
+`, clusterName, server, secretName, namespace)
+}
@@ -159,3 +159,37 @@ func TestApplyClusterKubeConfigSecret_LabelFromURLError(t *testing.T) {
 
 	mockKube.AssertExpectations(t)
 }
+
+func TestApplyClusterTokenSecret(t *testing.T) {
+	mockKube := new(mockKubeCLI)
+	svc := service.NewClusterService(mockKube)
+
+	mockKube.On("LabelFromURL", "https://10.0.0.1:6443").Return("10.0.0.1", nil).Once()
+	mockKube.On("Hash", "lab-1").Return("hash-lab-1").Once()
+	mockKube.On("EncodeBase64", "my-token").Return("encoded-token").Once()
+	mockKube.On("EncodeBase64", "my-ca").Return("encoded-ca").Once()
+	mockKube.On("ApplyYAML", mock.Anything, mock.MatchedBy(func(yaml string) bool {
+		return strings.Contains(yaml, "name: cluster-kubeconfig-hash-lab-1") &&
+			strings.Contains(yaml, "cluster-kubeconfig: 10.0.0.1") &&
+			strings.Contains(yaml, "token: encoded-token") &&
+			strings.Contains(yaml, "ca.crt: encoded-ca") &&
+			strings.Contains(yaml, "kind: VidraCluster") &&
+			strings.Contains(yaml, "credentialsType: Token") &&
+			strings.Contains(yaml, "server: https://10.0.0.1:6443")
+	})).Return(nil).Once()
+
+	err := svc.ApplyClusterTokenSecret("lab-1", "https://10.0.0.1:6443", "my-token", []byte("my-ca"), "vidra-system")
+	assert.NoError(t, err)
+	mockKube.AssertExpectations(t)
+}
+
+func TestApplyClusterTokenSecret_InvalidServer(t *testing.T) {
+	mockKube := new(mockKubeCLI)
+	svc := service.NewClusterService(mockKube)
+
+	mockKube.On("LabelFromURL", "not-a-url").Return("", errors.New("bad url")).Once()
+
+	err := svc.ApplyClusterTokenSecret("lab-1", "not-a-url", "my-token", nil, "vidra-system")
+	assert.ErrorContains(t, err, "invalid server URL")
+	mockKube.AssertExpectations(t)
+}
@@ -42,6 +42,18 @@ func (s *credentialsService) ApplyCredentialsSecret(url, username, password, nam
 	return s.kubecli.ApplyYAML(context.Background(), yaml)
 }
 
+func (s *credentialsService) ApplyCredentialsTokenSecret(url, token, namespace string) error {
+	hostname, err := s.kubecli.LabelFromURL(url)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to parse URL: %v\n", err)
+		fmt.Fprintf(os.Stderr, "trying input %s as URL\n", hostname)
+	}
+	credentialsID := s.kubecli.Hash(hostname)
+	yaml := generateTokenSecretYAML(credentialsID, namespace, hostname, s.kubecli.EncodeBase64(url), s.kubecli.EncodeBase64(token))
+	fmt.Print(yaml + "\n\n---\n")
+	return s.kubecli.ApplyYAML(context.Background(), yaml)
+}
+
 func (s *credentialsService) ListCredentialsSecrets() error {
 	result, err := s.kubecli.ListByLabel(
 		context.Background(),
@@ -84,3 +96,23 @@ data:
 		url,
 	)
 }
+
+func generateTokenSecretYAML(credentialsID, ns, trimmedUrl, url, token string) string {
+	// Generate the Secret YAML, the operator sends the token as X-INFRAHUB-KEY
+	return fmt.Sprintf(`apiVersion: v1
+kind: Secret
+metadata:
+  name: infrahub-credentials-%s
+  namespace: %s
+  labels:
+    infrahub-api-url: "%s"
+data:
+  token: %s
+  infrahubapiurl: %s`,
+		credentialsID,
+		ns,
+		trimmedUrl,
+		token,
+		url,
+	)
+}
@@ -100,3 +100,23 @@ func TestRemoveCredentialsSecret_LabelFromURL_Error(t *testing.T) {
 	assert.NoError(t, err)
 	mockCLI.AssertExpectations(t)
 }
+
+func TestApplyCredentialsTokenSecret_Success(t *testing.T) {
+	mockCLI := new(mockKubeCLI)
+	mockCLI.On("LabelFromURL", "https://infrahub.example.com").Return("infrahub.example.com", nil)
+	mockCLI.On("Hash", "infrahub.example.com").Return("abcd1234")
+	mockCLI.On("EncodeBase64", "https://infrahub.example.com").Return("base64-url")
+	mockCLI.On("EncodeBase64", "api-token").Return("base64-token")
+	mockCLI.On("ApplyYAML", mock.Anything, mock.MatchedBy(func(yaml string) bool {
+		return strings.Contains(yaml, "infrahub-credentials-abcd1234") &&
+			strings.Contains(yaml, "token: base64-token") &&
+			!strings.Contains(yaml, "username") &&
+			strings.Contains(yaml, "base64-url")
+	})).Return(nil)
+
+	svc := service.NewCredentialsService(mockCLI)
+	err := svc.ApplyCredentialsTokenSecret("https://infrahub.example.com", "api-token", "my-ns")
+
+	assert.NoError(t, err)
+	mockCLI.AssertExpectations(t)
+}
@@ -47,7 +47,7 @@ func (s *infrahubSyncService) ListInfrahubSync() error {
 		context.Background(),
 		"infrahubsyncs",
 		"",
-		"custom-columns=InfrahubSync-NAME:.metadata.name,NAMESPACE:.metadata.namespace,URL:.spec.source.infrahubAPIURL,ARTIFACT:.spec.source.artefactName,BRANCH:.spec.source.targetBranch,DATE:.spec.source.targetDate,DESTINATION_SERVER:.spec.destination.server,DESTINATION_NAMESPACE:.spec.destination.namespace,RECONCILE_ON_EVENTS:.spec.destination.reconcileOnEvents",
+		"custom-columns=InfrahubSync-NAME:.metadata.name,NAMESPACE:.metadata.namespace,URL:.spec.source.infrahubAPIURL,ARTIFACT:.spec.source.artefactName,BRANCH:.spec.source.targetBranch,DATE:.spec.source.targetDate,DESTINATION_SERVER:.spec.destination.server,DESTINATION_NAMESPACE:.spec.destination.namespace,RECONCILE_ON_EVENTS:.spec.destination.reconcileOnEvents,SUSPENDED:.spec.suspend",
 	)
 	fmt.Println(string(result) + "\n\n---\n")
 	return err
@@ -62,6 +62,18 @@ func (s *infrahubSyncService) RemoveInfrahubSync(url, namespace, name string) er
 	return s.kubecli.Delete(context.Background(), "infrahubSync", name, namespace)
 }
 
+func (s *infrahubSyncService) SuspendInfrahubSync(namespace, name string) error {
+	return s.setSuspend(namespace, name, true)
+}
+
+func (s *infrahubSyncService) ResumeInfrahubSync(namespace, name string) error {
+	return s.setSuspend(namespace, name, false)
+}
+
+func (s *infrahubSyncService) setSuspend(namespace, name string, suspend bool) error {
+	return s.kubecli.Patch(context.Background(), "infrahubSync", name, namespace, fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend))
+}
+
 func generateInfrahubSyncYAML(url, artifact, branch, date, server, destNamespace string, reconcileOnEvent bool, namespace, name string) string {
 	yaml := fmt.Sprintf(`apiVersion: infrahub.operators.com/v1alpha1
 kind: InfrahubSync
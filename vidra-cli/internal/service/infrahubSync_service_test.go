@@ -90,3 +90,25 @@ func TestRemoveInfrahubSync_LabelError(t *testing.T) {
 	assert.NoError(t, err)
 	mockCLI.AssertExpectations(t)
 }
+
+func TestSuspendInfrahubSync(t *testing.T) {
+	mockCLI := new(mockKubeCLI)
+	svc := service.NewInfrahubSyncService(mockCLI)
+
+	mockCLI.On("Patch", mock.Anything, "infrahubSync", "mysync", "default", `{"spec":{"suspend":true}}`).Return(nil)
+
+	err := svc.SuspendInfrahubSync("default", "mysync")
+	assert.NoError(t, err)
+	mockCLI.AssertExpectations(t)
+}
+
+func TestResumeInfrahubSync(t *testing.T) {
+	mockCLI := new(mockKubeCLI)
+	svc := service.NewInfrahubSyncService(mockCLI)
+
+	mockCLI.On("Patch", mock.Anything, "infrahubSync", "mysync", "default", `{"spec":{"suspend":false}}`).Return(errors.New("patch failed"))
+
+	err := svc.ResumeInfrahubSync("default", "mysync")
+	assert.EqualError(t, err, "patch failed")
+	mockCLI.AssertExpectations(t)
+}
@@ -2,6 +2,7 @@ package service
 
 type CredentialsService interface {
 	ApplyCredentialsSecret(url, username, password, namespace string) error
+	ApplyCredentialsTokenSecret(url, token, namespace string) error
 	PrintCredentialsSecret(url, namespace string) error
 	ListCredentialsSecrets() error
 	RemoveCredentialsSecret(url, namespace string) error
@@ -16,6 +17,7 @@ type ConfigService interface {
 
 type ClusterService interface {
 	ApplyClusterKubeConfigSecret(clusterName, namespace, kubeconfigPath string, loadKubeConfig KubeConfigLoader) error
+	ApplyClusterTokenSecret(clusterName, server, token string, caData []byte, namespace string) error
 	PrintClusterKubeConfigSecret(clusterName, namespace string) error
 	ListClusterKubeConfigSecrets() error
 	RemoveClusterKubeConfigSecret(clusterName, namespace string) error
@@ -26,4 +28,6 @@ type InfrahubSyncService interface {
 	RemoveInfrahubSync(urlStr, namespace, name string) error
 	PrintInfrahubSync(url, namespace, name string) error
 	ListInfrahubSync() error
+	SuspendInfrahubSync(namespace, name string) error
+	ResumeInfrahubSync(namespace, name string) error
 }
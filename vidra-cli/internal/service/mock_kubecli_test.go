@@ -48,3 +48,8 @@ func (m *mockKubeCLI) GetByName(ctx context.Context, kind, namespace, name strin
 	args := m.Called(ctx, kind, namespace, name)
 	return args.Get(0).([]byte), args.Error(1)
 }
+
+func (m *mockKubeCLI) Patch(ctx context.Context, kind, name, namespace, patch string) error {
+	args := m.Called(ctx, kind, name, namespace, patch)
+	return args.Error(0)
+}